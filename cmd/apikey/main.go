@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go-graphql/internal/apikey/dto"
+	apikey "go-graphql/internal/apikey/service"
+	"go-graphql/internal/config"
+	"go-graphql/internal/pkg/logger"
+	"go-graphql/internal/storage/sql"
+	"go-graphql/internal/storage/sql/sqlc"
+
+	"go.uber.org/zap"
+)
+
+const usage = `Usage: apikey [flags] create NAME
+
+Creates an API key straight in the database, e.g. the first admin key of a
+deployment where anonymous admin calls are rejected. The key is printed
+once, only its hash is stored.
+
+Flags:
+`
+
+func main() {
+	scopes := flag.String("scopes", apikey.ScopeAdmin, "comma separated scopes of the key, any of "+strings.Join(apikey.Scopes, ", "))
+	expires := flag.Duration("expires", 0, "lifetime of the key, 0 never expires")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 || flag.Arg(0) != "create" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	req := dto.AdminCreateAPIKeyRequest{Name: flag.Arg(1), Scopes: strings.Split(*scopes, ",")}
+	if *expires > 0 {
+		expiresAt := time.Now().Add(*expires)
+		req.ExpiresAt = &expiresAt
+	}
+	if err := run(req); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+func run(req dto.AdminCreateAPIKeyRequest) error {
+	config.LoadEnv()
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	levels, err := logger.NewLevels(cfg)
+	if err != nil {
+		return err
+	}
+	zapLog, err := logger.NewLogger(cfg, levels)
+	if err != nil {
+		return err
+	}
+	defer zapLog.Sync()
+	defer zap.ReplaceGlobals(zapLog)()
+
+	ctx := context.Background()
+	pool, err := sql.NewPool(ctx, cfg.Database, nil)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	key, err := apikey.New(sqlc.New(pool), zapLog).Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	log.Printf("✅ Created API key %d %q with scopes %s\n", key.ID, key.Name, strings.Join(key.Scopes, ","))
+	fmt.Println(key.Key)
+	return nil
+}
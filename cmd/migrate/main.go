@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"go-graphql/internal/config"
+	"go-graphql/internal/storage/sql/migrate"
+)
+
+const usage = `Usage: migrate [flags] <command> [arg]
+
+Commands:
+  up            apply every pending migration, under the migration lock
+  down N        roll back the last N migrations
+  goto V        migrate up or down to version V, 0 rolls back every migration
+  force V       set version V without running migrations (clears dirty state)
+  status        print the applied and the latest version
+  create NAME   create an empty up/down migration pair
+
+Flags:
+`
+
+func main() {
+	dir := flag.String("dir", migrate.Dir, "directory new migrations are created in")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), flag.Args()[1:], *dir); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+func run(command string, args []string, dir string) error {
+	// create only touches files, it must work without a database
+	if command == "create" {
+		if len(args) != 1 {
+			return fmt.Errorf("create expects a migration name")
+		}
+		paths, err := migrate.Create(dir, args[0])
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			log.Printf("✅ Created %s\n", path)
+		}
+		return nil
+	}
+
+	config.LoadEnv()
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	runner := migrate.NewRunner(cfg)
+
+	switch command {
+	case "up":
+		if err := runner.Up(context.Background()); err != nil {
+			return err
+		}
+	case "down":
+		n, err := intArg(command, args)
+		if err != nil {
+			return err
+		}
+		if err := runner.Down(n); err != nil {
+			return err
+		}
+	case "goto":
+		v, err := intArg(command, args)
+		if err != nil {
+			return err
+		}
+		if v < 0 {
+			return fmt.Errorf("goto expects a version greater than or equal to 0, got %d", v)
+		}
+		if err := runner.Goto(uint(v)); err != nil {
+			return err
+		}
+	case "force":
+		v, err := intArg(command, args)
+		if err != nil {
+			return err
+		}
+		if err := runner.Force(v); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return printStatus(runner)
+}
+
+func printStatus(runner *migrate.Runner) error {
+	status, err := runner.Status()
+	if err != nil {
+		return err
+	}
+	if !status.Applied {
+		log.Printf("📋 No migration applied, latest available: %d\n", status.Latest)
+		return nil
+	}
+	log.Printf("📋 Version: %d, latest available: %d, dirty: %t\n", status.Version, status.Latest, status.Dirty)
+	if status.Dirty {
+		log.Printf("⚠️  Database is dirty: fix the failed migration, then run `migrate force %d`\n", status.Version)
+	}
+	return nil
+}
+
+func intArg(command string, args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%s expects exactly one number", command)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("%s expects a number, got %q", command, args[0])
+	}
+	return n, nil
+}
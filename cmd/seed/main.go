@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"go-graphql/internal/config"
+	"go-graphql/internal/pkg/logger"
+	"go-graphql/internal/storage"
+	"go-graphql/internal/storage/cache"
+	"go-graphql/internal/storage/seed"
+	"go-graphql/internal/storage/sql"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+)
+
+const usage = `Usage: seed [flags] [file or directory ...]
+
+Loads YAML or JSON fixtures, by default every file in ./fixtures. Rows are
+upserted by id so loading the same fixtures twice is safe.
+
+Flags:
+`
+
+func main() {
+	reset := flag.Bool("reset", false, "truncate the seeded tables first (test and development only)")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"fixtures"}
+	}
+	if err := run(*reset, paths); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+func run(reset bool, paths []string) error {
+	config.LoadEnv()
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	levels, err := logger.NewLevels(cfg)
+	if err != nil {
+		return err
+	}
+	zapLog, err := logger.NewLogger(cfg, levels)
+	if err != nil {
+		return err
+	}
+	defer zapLog.Sync()
+	defer zap.ReplaceGlobals(zapLog)()
+
+	ctx := context.Background()
+	pool, err := sql.NewPool(ctx, cfg.Database, nil)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	loader := seed.NewLoader(storage.NewTxManager(pool, cfg, zapLog), cfg)
+	result, err := loader.LoadFiles(ctx, reset, paths...)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(result))
+	for key := range result {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	written := 0
+	for _, key := range keys {
+		log.Printf("✅ Inserted or updated %d %s\n", result[key], key)
+		written += result[key]
+	}
+
+	// an unchanged reseed keeps the cache and the ETags clients hold
+	if written > 0 || reset {
+		purgeCache(ctx, cfg)
+	}
+	return nil
+}
+
+// purgeCache drops cached products so the API serves the seeded rows, a
+// missing Redis is not fatal: the cache expires on its own
+func purgeCache(ctx context.Context, cfg *config.Config) {
+	client, err := cache.NewClient(cfg, otel.GetTracerProvider())
+	if err != nil {
+		log.Printf("⚠️  Cache not purged: %v\n", err)
+		return
+	}
+	defer client.Close()
+	store, err := cache.NewCacheStore(client, cfg)
+	if err != nil {
+		log.Printf("⚠️  Cache not purged: %v\n", err)
+		return
+	}
+	deleted, err := store.DeleteAll(ctx)
+	if err != nil {
+		log.Printf("⚠️  Cache not purged: %v\n", err)
+		return
+	}
+	log.Printf("✅ Purged %d cache keys\n", deleted)
+}
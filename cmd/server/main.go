@@ -9,6 +9,11 @@ import (
 // @in header
 // @name Authorization
 // @description Enter your JWT token in the format: Bearer <token>
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key scoped to products:read, products:write and/or admin
 func main() {
 	config.LoadEnv()
 
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-graphql/internal/apikey/dto"
+	"go-graphql/internal/apikey/service"
+	"go-graphql/internal/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminAPIKey struct {
+	Service *service.APIKey
+}
+
+func NewAdmin(s *service.APIKey) *AdminAPIKey {
+	return &AdminAPIKey{Service: s}
+}
+
+func (c *AdminAPIKey) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.POST("/", c.CreateAPIKey)
+	rg.GET("/", c.ListAPIKeys)
+	rg.DELETE("/:id", c.RevokeAPIKey)
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Generate an API key with the given scopes (products:read, products:write, admin). The key is only returned by this call
+// @Tags Admin API Keys
+// @Accept json
+// @Produce json
+// @Param key body dto.AdminCreateAPIKeyRequest true "API key to create"
+// @Success 201 {object} dto.AdminCreateAPIKeyResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/api-keys [post]
+func (c *AdminAPIKey) CreateAPIKey(ctx *gin.Context) {
+	var req dto.AdminCreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.JSONError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	key, err := c.Service.Create(ctx, req)
+	if errors.Is(err, service.ErrInvalidInput) {
+		response.JSONError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, key)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List every API key, revoked ones included. Keys are identified by their prefix
+// @Tags Admin API Keys
+// @Produce json
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/api-keys [get]
+func (c *AdminAPIKey) ListAPIKeys(ctx *gin.Context) {
+	keys, err := c.Service.List(ctx)
+	if err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke an API key by ID, requests using it are rejected at once
+// @Tags Admin API Keys
+// @Produce json
+// @Param id path int true "API key ID"
+// @Success 200 {object} dto.APIKeyResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/api-keys/{id} [delete]
+func (c *AdminAPIKey) RevokeAPIKey(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSONError(ctx, http.StatusBadRequest, response.ErrInvalidID)
+		return
+	}
+	key, err := c.Service.Revoke(ctx, int32(id))
+	if errors.Is(err, service.ErrNotFound) {
+		response.JSONError(ctx, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, key)
+}
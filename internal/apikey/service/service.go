@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-graphql/internal/apikey/dto"
+	"go-graphql/internal/pkg/logger"
+	storage "go-graphql/internal/storage/sql"
+	"go-graphql/internal/storage/sql/sqlc"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Scopes grantable to API keys
+const (
+	ScopeProductsRead  = "products:read"
+	ScopeProductsWrite = "products:write"
+	// ScopeAdmin reaches the operational admin routes, key management included
+	ScopeAdmin = "admin"
+)
+
+// Scopes lists every scope a key may be granted
+var Scopes = []string{ScopeProductsRead, ScopeProductsWrite, ScopeAdmin}
+
+var (
+	// ErrInvalidKey is returned for unknown, revoked and expired keys, the
+	// caller is not told which
+	ErrInvalidKey   = errors.New("invalid API key")
+	ErrInvalidInput = errors.New("invalid API key request")
+	ErrNotFound     = errors.New("API key not found or already revoked")
+)
+
+const (
+	// keyPrefix tells API keys apart from other secrets in logs and scanners
+	keyPrefix = "ggk_"
+	// displayLen characters of a key are stored in clear to identify it
+	displayLen = len(keyPrefix) + 8
+	// lastUsedPrecision bounds how often last_used_at is written for a key
+	lastUsedPrecision = time.Minute
+)
+
+// Principal is the caller authenticated by an API key
+type Principal struct {
+	KeyID  int32
+	Name   string
+	Scopes []string
+}
+
+// HasScope reports whether the key was granted scope
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal records the caller authenticated by an API key
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the caller recorded by WithPrincipal, ok is false
+// when the request was not authenticated by an API key
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+type APIKey struct {
+	query *sqlc.Queries
+	log   *zap.Logger
+}
+
+func New(q *sqlc.Queries, log *zap.Logger) *APIKey {
+	return &APIKey{query: q, log: log.Named("apikey")}
+}
+
+// Create generates a key, only its hash is stored so the returned key
+// cannot be read again
+func (s *APIKey) Create(ctx context.Context, req dto.AdminCreateAPIKeyRequest) (dto.AdminCreateAPIKeyResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return dto.AdminCreateAPIKeyResponse{}, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+	if len(req.Scopes) == 0 {
+		return dto.AdminCreateAPIKeyResponse{}, fmt.Errorf("%w: at least one scope is required", ErrInvalidInput)
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			return dto.AdminCreateAPIKeyResponse{}, fmt.Errorf("%w: unknown scope %q, expected any of %s",
+				ErrInvalidInput, scope, strings.Join(Scopes, ", "))
+		}
+	}
+	arg := sqlc.CreateAPIKeyParams{
+		KeyName: req.Name,
+		Scopes:  slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return dto.AdminCreateAPIKeyResponse{}, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidInput)
+		}
+		arg.ExpiresAt = pgtype.Timestamp{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+
+	key, err := generateKey()
+	if err != nil {
+		return dto.AdminCreateAPIKeyResponse{}, err
+	}
+	arg.KeyPrefix = key[:displayLen]
+	arg.KeyHash = hashKey(key)
+
+	created, err := s.query.CreateAPIKey(ctx, arg)
+	if err != nil {
+		return dto.AdminCreateAPIKeyResponse{}, err
+	}
+	logger.FromContext(ctx, s.log).Info("API key created",
+		zap.Int32("id", created.ID), zap.String("name", created.KeyName), zap.Strings("scopes", created.Scopes))
+	return dto.AdminCreateAPIKeyResponse{APIKeyResponse: toResponse(created), Key: key}, nil
+}
+
+func (s *APIKey) List(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	keys, err := s.query.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, toResponse(key))
+	}
+	return resp, nil
+}
+
+// Revoke disables a key for good, revoked keys stay listed
+func (s *APIKey) Revoke(ctx context.Context, id int32) (dto.APIKeyResponse, error) {
+	key, err := s.query.RevokeAPIKey(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dto.APIKeyResponse{}, ErrNotFound
+	}
+	if err != nil {
+		return dto.APIKeyResponse{}, err
+	}
+	logger.FromContext(ctx, s.log).Info("API key revoked", zap.Int32("id", key.ID), zap.String("name", key.KeyName))
+	return toResponse(key), nil
+}
+
+// Authenticate returns the caller owning raw, keys are read from the
+// primary so a revocation applies at once
+func (s *APIKey) Authenticate(ctx context.Context, raw string) (Principal, error) {
+	if !strings.HasPrefix(raw, keyPrefix) {
+		return Principal{}, ErrInvalidKey
+	}
+	key, err := s.query.GetAPIKeyByHash(storage.WithPrimary(ctx, true), hashKey(raw))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Principal{}, ErrInvalidKey
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+
+	log := logger.FromContext(ctx, s.log)
+	// the timestamp columns hold UTC wall clock times
+	now := time.Now().UTC()
+	switch {
+	case key.RevokedAt.Valid:
+		log.Warn("Revoked API key used", zap.Int32("id", key.ID))
+		return Principal{}, ErrInvalidKey
+	case key.ExpiresAt.Valid && !now.Before(key.ExpiresAt.Time):
+		log.Warn("Expired API key used", zap.Int32("id", key.ID))
+		return Principal{}, ErrInvalidKey
+	}
+
+	if !key.LastUsedAt.Valid || now.Sub(key.LastUsedAt.Time) >= lastUsedPrecision {
+		if err := s.query.TouchAPIKey(ctx, key.ID); err != nil {
+			log.Warn("Failed to record API key use", zap.Int32("id", key.ID), zap.Error(err))
+		}
+	}
+	return Principal{KeyID: key.ID, Name: key.KeyName, Scopes: key.Scopes}, nil
+}
+
+func generateKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generate API key: %w", err)
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashKey needs no salt nor stretching, keys are random 256-bit secrets
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func toResponse(key sqlc.ApiKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.KeyName,
+		Prefix:     key.KeyPrefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  timePtr(key.ExpiresAt),
+		LastUsedAt: timePtr(key.LastUsedAt),
+		RevokedAt:  timePtr(key.RevokedAt),
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func timePtr(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	return &ts.Time
+}
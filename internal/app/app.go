@@ -1,51 +1,82 @@
 package app
 
 import (
+	"time"
+
 	"go-graphql/internal/config" // gqlgen generated package
 	// your resolvers
+	apikeyController "go-graphql/internal/apikey/controller"
+	apikeyService "go-graphql/internal/apikey/service"
+	"go-graphql/internal/graph/persisted"
 	"go-graphql/internal/health"
+	"go-graphql/internal/idempotency"
+	"go-graphql/internal/loglevel"
+	"go-graphql/internal/metrics"
 	"go-graphql/internal/pkg/logger"
 	productController "go-graphql/internal/product/controller"
 	productService "go-graphql/internal/product/service"
+	"go-graphql/internal/ratelimit"
 	"go-graphql/internal/server"
+	"go-graphql/internal/storage"
 	"go-graphql/internal/storage/cache"
 	"go-graphql/internal/storage/sql"
 	"go-graphql/internal/storage/sql/migrate"
 	"go-graphql/internal/storage/sql/sqlc"
+	"go-graphql/internal/tracing"
 
 	"go.uber.org/fx"
 )
 
 func NewApp() *fx.App {
 	return fx.New(
+		// draining and shutting down the HTTP server may take up to
+		// HTTPMaxStopTime, leave some room for the other stop hooks
+		fx.StopTimeout(time.Duration(config.HTTPMaxStopTime+15)*time.Second),
 		fx.Provide(
-			logger.NewLogger,
 			config.NewConfig,
+			logger.NewLevels,
+			logger.NewLogger,
+			tracing.NewTracerProvider,
 			sql.InitialDB,
+			sql.NewRouter,
+			sql.NewDBTX,
 			// health check
 			health.New,
+			loglevel.New,
+			metrics.New,
 			// server
 			server.NewGinEngine,
 			server.NewHTTPServer,
 			// db
 			migrate.NewRunner,
+			migrate.NewReadiness,
 			sqlc.New,
+			storage.NewTxManager,
 			// cache
 			cache.NewClient,
 			cache.NewCacheStore,
+			ratelimit.New,
+			idempotency.New,
 			//controller
 			productController.NewAdmin,
 			productController.NewClient,
+			productController.NewAdminCache,
+			apikeyController.NewAdmin,
 			// services
 			productService.New,
+			apikeyService.New,
 			// GraphQL
 			server.NewGraphQLResolver,
+			persisted.NewQueryCache,
+			persisted.NewManifest,
 		),
 		fx.Invoke(
 			server.RegisterRoutes,
 			server.StartHTTPServer,
 			// migration
 			migrate.RunMigrations,
+			// cache
+			productService.RegisterWarmup,
 			// life cycle
 			logger.RegisterLoggerLifecycle,
 		),
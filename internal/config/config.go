@@ -3,40 +3,295 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	HTTPPort       int
-	HTTPAddress    string
-	Database       DatabaseCfg
-	ENV            string
-	Redis          RedisCfg
+	HTTPPort    int
+	HTTPAddress string
+	HTTP        HTTPCfg
+	Database    DatabaseCfg
+	ENV         string
+	Redis       RedisCfg
+	Log         LogCfg
+	Tracing     TracingCfg
+	RateLimit   RateLimitCfg
+	GraphQL     GraphQLCfg
+	Idempotency IdempotencyCfg
+}
+
+// HTTPMaxStopTime caps, in seconds, HTTPCfg.DrainPeriod plus
+// HTTPCfg.ShutdownTimeout, the app gives up stopping after it
+const HTTPMaxStopTime = 120
+
+type HTTPCfg struct {
+	ReadTimeout       int // in seconds, whole request including the body, 0 disables
+	ReadHeaderTimeout int // in seconds, 0 falls back to ReadTimeout
+	WriteTimeout      int // in seconds, keep it above the route timeouts, 0 disables
+	IdleTimeout       int // in seconds, keep-alive connections, 0 falls back to ReadTimeout
+	DrainPeriod       int // in seconds, readiness fails for this long before listeners close
+	ShutdownTimeout   int // in seconds, in-flight requests are cut after it
+	RequestTimeout    int // in seconds, client REST routes, 0 disables
+	AdminTimeout      int // in seconds, admin REST routes, 0 disables
+	GraphQLTimeout    int // in seconds, GraphQL queries and mutations over HTTP, 0 disables
+	TLS               HTTPTLSCfg
+	H2C               bool // serve HTTP/2 without TLS, for internal traffic behind a proxy
+	// TrustedProxies lists the addresses or CIDRs allowed to set
+	// X-Forwarded-For, the client IP is the peer address when empty
+	TrustedProxies []string
+	CORS           CORSCfg
+	Security       SecurityCfg
+	// AdminAnonymous lets callers without an API key nor a client
+	// certificate reach the admin routes, on by default in development only
+	// and ignored once TLS.ClientCAFile is set
+	AdminAnonymous bool
+	Compression    CompressionCfg
+	CacheControl   CacheControlCfg
+}
+
+// CORSCfg lets browser apps on other origins call the API, unset values
+// fall back to the defaults of the environment, see setHTTPDefaults
+type CORSCfg struct {
+	AllowedOrigins   []string // scheme://host[:port], * allows any origin without credentials
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string // response headers readable by scripts
+	AllowCredentials bool     // cookies and client certificates, requires explicit origins
+	MaxAge           int      // in seconds, browsers cache preflight responses for it
+}
+
+// SecurityCfg sets the security headers of every response, unset values
+// fall back to the defaults of the environment, see setHTTPDefaults
+type SecurityCfg struct {
+	HSTSMaxAge            int    // in seconds, 0 disables Strict-Transport-Security
+	HSTSIncludeSubdomains bool   // extends HSTS to every subdomain
+	FrameOptions          string // DENY or SAMEORIGIN
+	ContentSecurityPolicy string // API responses
+	// DocsContentSecurityPolicy applies to the playground and swagger pages
+	// which load scripts and styles
+	DocsContentSecurityPolicy string
+}
+
+// CompressionCfg compresses responses with brotli or gzip, whichever the
+// client prefers in Accept-Encoding
+type CompressionCfg struct {
+	Enabled bool
+	MinSize int // in bytes, smaller responses are not worth compressing
+}
+
+// CacheControlCfg sets the Cache-Control of successful public reads so
+// CDNs may cache them, unset values fall back to setHTTPDefaults
+type CacheControlCfg struct {
+	Products string // GET /api/v1/products
+	Product  string // GET /api/v1/products/{id}
+}
+
+type HTTPTLSCfg struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables mutual TLS on admin routes, clients of the other
+	// routes are not asked for a certificate
+	ClientCAFile   string
+	MinVersion     string // 1.2 (default) or 1.3
+	ReloadInterval int    // in seconds, rotated certificate files are picked up within it, 0 disables
 }
 
 type DatabaseCfg struct {
-	DSN string
+	DSN              string
+	ReplicaDSNs      []string // read replicas, list/search/get queries are routed to them
+	ReplicaCheck     int      // in seconds, interval between replica health checks
+	MaxOpenConns     int      // 0 keeps the pgxpool default
+	MinConns         int      // connections kept open even when idle, pgxpool has no idle cap
+	ConnMaxLifetime  int      // in seconds, 0 keeps the pgxpool default
+	ConnMaxIdleTime  int      // in seconds, idle connections above MinConns are closed after it
+	StatementTimeout int      // in milliseconds, 0 disables the timeout
+	PingOnStart      bool     // fail start when the database is unreachable
+	PingRetries      int
+	PingBackoff      int  // in milliseconds, doubled after every failed ping
+	TxMaxRetries     int  // retries of transactions failing with serialization errors or deadlocks
+	AutoMigrate      bool // apply pending migrations on start, under an advisory lock
+	MigrateTimeout   int  // in seconds, covers waiting for the lock and running migrations
 }
 
+// Redis deployment modes, see RedisCfg.Mode
+const (
+	RedisModeSingle   = "single"
+	RedisModeSentinel = "sentinel"
+	RedisModeCluster  = "cluster"
+)
+
 type RedisCfg struct {
-	DSN        string
-	DB         int
-	Prefix     string
-	DefaultTTL int // in minute
+	// DSN is a comma separated list of host:port, redis:// or rediss://
+	// addresses: the node, the Sentinel nodes or the cluster seed nodes
+	DSN               string
+	Mode              string // single (default), sentinel or cluster
+	Username          string
+	Password          string
+	MasterName        string // Sentinel master name
+	SentinelUsername  string
+	SentinelPassword  string
+	TLS               RedisTLSCfg
+	DB                int
+	Prefix            string
+	DefaultTTL        int    // in minute
+	Codec             string // json (default) or msgpack
+	Version           int    // bump to invalidate every cached entry
+	CompressThreshold int    // in bytes, 0 disables compression
+	WarmupEnabled     bool   // preload hot keys on start
+	WarmupTopN        int    // number of latest products preloaded individually
+}
+
+type RedisTLSCfg struct {
+	Enabled            bool
+	CAFile             string
+	CertFile           string // client certificate for mutual TLS
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+type LogCfg struct {
+	Level              string // debug, info (default), warn or error
+	Format             string // json (default) or console
+	SamplingInitial    int    // entries per second and message logged as is, 0 disables sampling
+	SamplingThereafter int    // past SamplingInitial, only every Nth entry is logged
+	File               string // also write to this file, rotated, empty logs to stdout only
+	FileMaxSize        int    // in megabytes, size that triggers a rotation
+	FileMaxBackups     int    // rotated files kept, 0 keeps them all
+	FileMaxAge         int    // in days, 0 keeps rotated files regardless of age
+	FileCompress       bool   // gzip rotated files
+	// Packages overrides the level of named loggers, as a comma separated
+	// list of name=level, e.g. sql=warn,product=debug
+	Packages string
+}
+
+// Tracing exporters, see TracingCfg.Exporter
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+type TracingCfg struct {
+	Enabled     bool
+	Exporter    string  // otlp (default), stdout or file
+	Endpoint    string  // OTLP/HTTP collector as host:port, empty uses the OTEL_EXPORTER_OTLP_* variables
+	Insecure    bool    // send to the collector over plain HTTP
+	File        string  // spans are appended to this file by the file exporter
+	SampleRatio float64 // share of new traces recorded, from 0 to 1, sampled parents are always followed
+	ServiceName string  // defaults to go-graphql
+}
+
+// RateLimitCfg configures the token buckets kept in Redis per client and
+// route group, a bucket holds Burst tokens and refills at Rate per minute
+type RateLimitCfg struct {
+	Enabled      bool
+	FailOpen     bool // let requests through while Redis is unreachable
+	ClientRate   int  // requests per minute on /api/v1/products, 0 disables
+	ClientBurst  int  // 0 falls back to ClientRate
+	AdminRate    int  // requests per minute on /api/v1/admin, 0 disables
+	AdminBurst   int  // 0 falls back to AdminRate
+	GraphQLRate  int  // query cost per minute on /query, 0 disables
+	GraphQLBurst int  // 0 falls back to GraphQLRate, also caps the cost of one query
+}
+
+// GraphQL transports, see GraphQLCfg.Transports
+const (
+	GraphQLTransportGET       = "get"
+	GraphQLTransportPOST      = "post"
+	GraphQLTransportMultipart = "multipart"
+	GraphQLTransportWebSocket = "websocket"
+)
+
+// GraphQLCfg configures the GraphQL handler and bounds the work a single
+// operation can ask for
+type GraphQLCfg struct {
+	Transports         []string // any of get, post, multipart and websocket
+	QueryCacheSize     int      // parsed documents kept in memory, 0 disables the cache
+	WebSocketKeepAlive int      // in seconds, ping interval of WebSocket connections, 0 disables
+	MaxComplexity      int      // query cost, list fields cost their limit times their selection, 0 disables
+	MaxDepth           int      // nesting of selection sets, introspection excluded, 0 disables
+	MaxLimit           int      // largest pagination limit of GraphQL and the REST lists, 0 disables
+	APQEnabled         bool
+	APQTTL             int // in minutes, persisted queries unused for this long are forgotten
+	// TrustedDocuments is a JSON manifest of sha256 hash to document,
+	// clients send the hash instead of the query
+	TrustedDocuments string
+	TrustedOnly      bool // reject every operation missing from TrustedDocuments
+}
+
+// IdempotencyCfg configures the Idempotency-Key support of admin writes
+type IdempotencyCfg struct {
+	Enabled     bool
+	TTL         int // in minutes, responses are replayed to retries for this long
+	LockTimeout int // in seconds, a retry waits this long for the first request before running again
+}
+
+// PackageLevels parses LogCfg.Packages into logger name to level
+func (c LogCfg) PackageLevels() (map[string]string, error) {
+	levels := map[string]string{}
+	for _, item := range splitList(c.Packages) {
+		name, level, ok := strings.Cut(item, "=")
+		name, level = strings.TrimSpace(name), strings.TrimSpace(level)
+		if !ok || name == "" || level == "" {
+			return nil, fmt.Errorf("%q is not a name=level pair", item)
+		}
+		levels[name] = level
+	}
+	return levels, nil
+}
+
+// String masks the Redis credentials so the config can be logged safely
+func (c RedisCfg) String() string {
+	type plain RedisCfg
+	if c.Password != "" {
+		c.Password = "***"
+	}
+	if c.SentinelPassword != "" {
+		c.SentinelPassword = "***"
+	}
+	c.DSN = redactDSNs(c.DSN)
+	return fmt.Sprintf("%+v", plain(c))
+}
+
+// redactDSNs masks the userinfo of every redis:// or rediss:// URL in a
+// comma separated DSN list
+func redactDSNs(dsn string) string {
+	items := strings.Split(dsn, ",")
+	for i, item := range items {
+		u, err := url.Parse(strings.TrimSpace(item))
+		if err != nil || u.User == nil {
+			continue
+		}
+		u.User = nil
+		scheme, rest, _ := strings.Cut(u.String(), "://")
+		items[i] = scheme + "://***@" + rest
+	}
+	return strings.Join(items, ",")
 }
 
 func NewConfig() (*Config, error) {
 	v := viper.New()
 	v.SetEnvPrefix("APP")
 	v.AutomaticEnv()
+	setHTTPDefaults(v, v.GetString("ENV"))
 
 	// Build config
 	cfg := buildConfig(v)
 
+	if v.IsSet("DATABASE_MAX_IDLE_CONNS") {
+		log.Printf("⚠️  WARNING: DATABASE_MAX_IDLE_CONNS is no longer read. " +
+			"Set APP_DATABASE_MIN_CONNS and APP_DATABASE_CONN_MAX_IDLE_TIME instead\n")
+	}
+
 	// Validate configuration
 	if err := ValidateConfig(cfg); err != nil {
 		return nil, fmt.Errorf("⚠️ invalid configuration: %w", err)
@@ -52,16 +307,194 @@ func buildConfig(v *viper.Viper) *Config {
 		HTTPPort:    v.GetInt("HTTP_PORT"),
 		HTTPAddress: v.GetString("HTTP_ADDRESS"),
 		ENV:         v.GetString("ENV"),
+		HTTP: HTTPCfg{
+			ReadTimeout:       v.GetInt("HTTP_READ_TIMEOUT"),
+			ReadHeaderTimeout: v.GetInt("HTTP_READ_HEADER_TIMEOUT"),
+			WriteTimeout:      v.GetInt("HTTP_WRITE_TIMEOUT"),
+			IdleTimeout:       v.GetInt("HTTP_IDLE_TIMEOUT"),
+			DrainPeriod:       v.GetInt("HTTP_DRAIN_PERIOD"),
+			ShutdownTimeout:   v.GetInt("HTTP_SHUTDOWN_TIMEOUT"),
+			RequestTimeout:    v.GetInt("HTTP_REQUEST_TIMEOUT"),
+			AdminTimeout:      v.GetInt("HTTP_ADMIN_TIMEOUT"),
+			GraphQLTimeout:    v.GetInt("HTTP_GRAPHQL_TIMEOUT"),
+			AdminAnonymous:    v.GetBool("HTTP_ADMIN_ANONYMOUS"),
+			TLS: HTTPTLSCfg{
+				Enabled:        v.GetBool("HTTP_TLS_ENABLED"),
+				CertFile:       v.GetString("HTTP_TLS_CERT_FILE"),
+				KeyFile:        v.GetString("HTTP_TLS_KEY_FILE"),
+				ClientCAFile:   v.GetString("HTTP_TLS_CLIENT_CA_FILE"),
+				MinVersion:     v.GetString("HTTP_TLS_MIN_VERSION"),
+				ReloadInterval: v.GetInt("HTTP_TLS_RELOAD_INTERVAL"),
+			},
+			H2C:            v.GetBool("HTTP_H2C"),
+			TrustedProxies: splitList(v.GetString("HTTP_TRUSTED_PROXIES")),
+			CORS: CORSCfg{
+				AllowedOrigins:   splitList(v.GetString("HTTP_CORS_ALLOWED_ORIGINS")),
+				AllowedMethods:   splitList(strings.ToUpper(v.GetString("HTTP_CORS_ALLOWED_METHODS"))),
+				AllowedHeaders:   splitList(v.GetString("HTTP_CORS_ALLOWED_HEADERS")),
+				ExposedHeaders:   splitList(v.GetString("HTTP_CORS_EXPOSED_HEADERS")),
+				AllowCredentials: v.GetBool("HTTP_CORS_ALLOW_CREDENTIALS"),
+				MaxAge:           v.GetInt("HTTP_CORS_MAX_AGE"),
+			},
+			Security: SecurityCfg{
+				HSTSMaxAge:                v.GetInt("HTTP_HSTS_MAX_AGE"),
+				HSTSIncludeSubdomains:     v.GetBool("HTTP_HSTS_INCLUDE_SUBDOMAINS"),
+				FrameOptions:              strings.ToUpper(v.GetString("HTTP_FRAME_OPTIONS")),
+				ContentSecurityPolicy:     v.GetString("HTTP_CONTENT_SECURITY_POLICY"),
+				DocsContentSecurityPolicy: v.GetString("HTTP_DOCS_CONTENT_SECURITY_POLICY"),
+			},
+			Compression: CompressionCfg{
+				Enabled: v.GetBool("HTTP_COMPRESSION_ENABLED"),
+				MinSize: v.GetInt("HTTP_COMPRESSION_MIN_SIZE"),
+			},
+			CacheControl: CacheControlCfg{
+				Products: v.GetString("HTTP_CACHE_CONTROL_PRODUCTS"),
+				Product:  v.GetString("HTTP_CACHE_CONTROL_PRODUCT"),
+			},
+		},
 		Database: DatabaseCfg{
-			DSN: v.GetString("DATABASE_DSN"),
+			DSN:              v.GetString("DATABASE_DSN"),
+			ReplicaDSNs:      splitList(v.GetString("DATABASE_REPLICA_DSNS")),
+			ReplicaCheck:     v.GetInt("DATABASE_REPLICA_CHECK"),
+			MaxOpenConns:     v.GetInt("DATABASE_MAX_OPEN_CONNS"),
+			MinConns:         v.GetInt("DATABASE_MIN_CONNS"),
+			ConnMaxLifetime:  v.GetInt("DATABASE_CONN_MAX_LIFETIME"),
+			ConnMaxIdleTime:  v.GetInt("DATABASE_CONN_MAX_IDLE_TIME"),
+			StatementTimeout: v.GetInt("DATABASE_STATEMENT_TIMEOUT"),
+			PingOnStart:      v.GetBool("DATABASE_PING_ON_START"),
+			PingRetries:      v.GetInt("DATABASE_PING_RETRIES"),
+			PingBackoff:      v.GetInt("DATABASE_PING_BACKOFF"),
+			TxMaxRetries:     v.GetInt("DATABASE_TX_MAX_RETRIES"),
+			AutoMigrate:      v.GetBool("DATABASE_AUTO_MIGRATE"),
+			MigrateTimeout:   v.GetInt("DATABASE_MIGRATE_TIMEOUT"),
 		},
 		Redis: RedisCfg{
-			DSN:        v.GetString("REDIS_DSN"),
-			DB:         v.GetInt("REDIS_DB"),
-			Prefix:     v.GetString("REDIS_PREFIX"),
-			DefaultTTL: v.GetInt("REDIS_DEFAULT_TTL"),
+			DSN:              v.GetString("REDIS_DSN"),
+			Mode:             v.GetString("REDIS_MODE"),
+			Username:         v.GetString("REDIS_USERNAME"),
+			Password:         v.GetString("REDIS_PASSWORD"),
+			MasterName:       v.GetString("REDIS_MASTER_NAME"),
+			SentinelUsername: v.GetString("REDIS_SENTINEL_USERNAME"),
+			SentinelPassword: v.GetString("REDIS_SENTINEL_PASSWORD"),
+			TLS: RedisTLSCfg{
+				Enabled:            v.GetBool("REDIS_TLS_ENABLED"),
+				CAFile:             v.GetString("REDIS_TLS_CA_FILE"),
+				CertFile:           v.GetString("REDIS_TLS_CERT_FILE"),
+				KeyFile:            v.GetString("REDIS_TLS_KEY_FILE"),
+				ServerName:         v.GetString("REDIS_TLS_SERVER_NAME"),
+				InsecureSkipVerify: v.GetBool("REDIS_TLS_INSECURE_SKIP_VERIFY"),
+			},
+			DB:                v.GetInt("REDIS_DB"),
+			Prefix:            v.GetString("REDIS_PREFIX"),
+			DefaultTTL:        v.GetInt("REDIS_DEFAULT_TTL"),
+			Codec:             v.GetString("REDIS_CODEC"),
+			Version:           v.GetInt("REDIS_VERSION"),
+			CompressThreshold: v.GetInt("REDIS_COMPRESS_THRESHOLD"),
+			WarmupEnabled:     v.GetBool("REDIS_WARMUP_ENABLED"),
+			WarmupTopN:        v.GetInt("REDIS_WARMUP_TOP_N"),
+		},
+		Log: LogCfg{
+			Level:              v.GetString("LOG_LEVEL"),
+			Format:             v.GetString("LOG_FORMAT"),
+			SamplingInitial:    v.GetInt("LOG_SAMPLING_INITIAL"),
+			SamplingThereafter: v.GetInt("LOG_SAMPLING_THEREAFTER"),
+			File:               v.GetString("LOG_FILE"),
+			FileMaxSize:        v.GetInt("LOG_FILE_MAX_SIZE"),
+			FileMaxBackups:     v.GetInt("LOG_FILE_MAX_BACKUPS"),
+			FileMaxAge:         v.GetInt("LOG_FILE_MAX_AGE"),
+			FileCompress:       v.GetBool("LOG_FILE_COMPRESS"),
+			Packages:           v.GetString("LOG_PACKAGES"),
+		},
+		Tracing: TracingCfg{
+			Enabled:     v.GetBool("TRACING_ENABLED"),
+			Exporter:    v.GetString("TRACING_EXPORTER"),
+			Endpoint:    v.GetString("TRACING_ENDPOINT"),
+			Insecure:    v.GetBool("TRACING_INSECURE"),
+			File:        v.GetString("TRACING_FILE"),
+			SampleRatio: v.GetFloat64("TRACING_SAMPLE_RATIO"),
+			ServiceName: v.GetString("TRACING_SERVICE_NAME"),
 		},
+		RateLimit: RateLimitCfg{
+			Enabled:      v.GetBool("RATE_LIMIT_ENABLED"),
+			FailOpen:     v.GetBool("RATE_LIMIT_FAIL_OPEN"),
+			ClientRate:   v.GetInt("RATE_LIMIT_CLIENT_RATE"),
+			ClientBurst:  v.GetInt("RATE_LIMIT_CLIENT_BURST"),
+			AdminRate:    v.GetInt("RATE_LIMIT_ADMIN_RATE"),
+			AdminBurst:   v.GetInt("RATE_LIMIT_ADMIN_BURST"),
+			GraphQLRate:  v.GetInt("RATE_LIMIT_GRAPHQL_RATE"),
+			GraphQLBurst: v.GetInt("RATE_LIMIT_GRAPHQL_BURST"),
+		},
+		GraphQL: GraphQLCfg{
+			Transports:         splitList(strings.ToLower(v.GetString("GRAPHQL_TRANSPORTS"))),
+			QueryCacheSize:     v.GetInt("GRAPHQL_QUERY_CACHE_SIZE"),
+			WebSocketKeepAlive: v.GetInt("GRAPHQL_WEBSOCKET_KEEPALIVE"),
+			MaxComplexity:      v.GetInt("GRAPHQL_MAX_COMPLEXITY"),
+			MaxDepth:           v.GetInt("GRAPHQL_MAX_DEPTH"),
+			MaxLimit:           v.GetInt("GRAPHQL_MAX_LIMIT"),
+			APQEnabled:         v.GetBool("GRAPHQL_APQ_ENABLED"),
+			APQTTL:             v.GetInt("GRAPHQL_APQ_TTL"),
+			TrustedDocuments:   v.GetString("GRAPHQL_TRUSTED_DOCUMENTS"),
+			TrustedOnly:        v.GetBool("GRAPHQL_TRUSTED_ONLY"),
+		},
+		Idempotency: IdempotencyCfg{
+			Enabled:     v.GetBool("IDEMPOTENCY_ENABLED"),
+			TTL:         v.GetInt("IDEMPOTENCY_TTL"),
+			LockTimeout: v.GetInt("IDEMPOTENCY_LOCK_TIMEOUT"),
+		},
+	}
+}
+
+// setHTTPDefaults fills the CORS, security and caching header settings
+// missing from the environment. Development lets local frontends in and
+// revalidates every read, production only trusts the origins it is given
+// and pins HTTPS with HSTS
+func setHTTPDefaults(v *viper.Viper, env string) {
+	v.SetDefault("HTTP_CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE")
+	v.SetDefault("HTTP_CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-API-Key,Idempotency-Key,X-Request-ID")
+	v.SetDefault("HTTP_CORS_EXPOSED_HEADERS",
+		"X-Request-ID,Retry-After,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,Idempotent-Replayed,ETag,Link")
+	v.SetDefault("HTTP_CORS_MAX_AGE", 600)
+	v.SetDefault("HTTP_FRAME_OPTIONS", "DENY")
+	v.SetDefault("HTTP_CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'")
+	v.SetDefault("HTTP_DOCS_CONTENT_SECURITY_POLICY",
+		"default-src 'self'; script-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; "+
+			"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; img-src 'self' data: https:; "+
+			"font-src 'self' data: https://cdn.jsdelivr.net; connect-src 'self' ws: wss:; frame-ancestors 'none'")
+	v.SetDefault("HTTP_CACHE_CONTROL_PRODUCTS", "public, max-age=30, stale-while-revalidate=30")
+	v.SetDefault("HTTP_CACHE_CONTROL_PRODUCT", "public, max-age=60, stale-while-revalidate=60")
+
+	switch env {
+	case "development":
+		v.SetDefault("HTTP_CORS_ALLOWED_ORIGINS", "http://localhost:3000,http://127.0.0.1:3000")
+		v.SetDefault("HTTP_CORS_ALLOW_CREDENTIALS", true)
+		// local admin calls need no API key
+		v.SetDefault("HTTP_ADMIN_ANONYMOUS", true)
+		// local frontends revalidate every read
+		v.SetDefault("HTTP_CACHE_CONTROL_PRODUCTS", "no-cache")
+		v.SetDefault("HTTP_CACHE_CONTROL_PRODUCT", "no-cache")
+	case "production":
+		v.SetDefault("HTTP_HSTS_MAX_AGE", 31536000)
+		v.SetDefault("HTTP_HSTS_INCLUDE_SUBDOMAINS", true)
+	}
+}
+
+// splitList splits a comma separated environment value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// HTTPScheme returns the scheme clients use to reach the server
+func (cfg *Config) HTTPScheme() string {
+	if cfg.HTTP.TLS.Enabled {
+		return "https"
 	}
+	return "http"
 }
 
 func (cfg *Config) IsTest() bool {
@@ -72,6 +505,11 @@ func (cfg *Config) IsDevelopment() bool {
 	return cfg.ENV == "development"
 }
 
+// GraphQLTransport reports whether the GraphQL transport is enabled
+func (cfg *Config) GraphQLTransport(name string) bool {
+	return slices.Contains(cfg.GraphQL.Transports, name)
+}
+
 // IsProduction returns true if environment is production
 func (cfg *Config) IsProduction() bool {
 	return cfg.ENV == "production"
@@ -4,21 +4,55 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/url"
+	"os"
+	"regexp"
 	"strings"
 )
 
+// cacheDirective matches one Cache-Control directive with its optional value
+var cacheDirective = regexp.MustCompile(`^[a-zA-Z-]+(=([0-9]+|"[^"]*"|[a-zA-Z-]+))?$`)
+
 // ValidateConfig validates all configuration values and returns detailed errors
 func ValidateConfig(cfg *Config) error {
 	// Run all validation checks
 	checks := []func(*Config) error{
 		validateHTTPPort,
 		validateHTTPAddress,
+		validateHTTPTimeouts,
+		validateHTTPShutdown,
+		validateHTTPAdmin,
+		validateHTTPTLS,
+		validateHTTPTrustedProxies,
+		validateHTTPCORS,
+		validateHTTPSecurity,
+		validateHTTPCompression,
+		validateHTTPCacheControl,
 		validateEnvironment,
 		validateDatabaseDSN,
+		validateDatabaseReplicas,
+		validateDatabasePool,
+		validateDatabasePing,
+		validateDatabaseMigrate,
 		validateRedisDSN,
+		validateRedisMode,
+		validateRedisTLS,
 		validateRedisDB,
 		validateRedisPrefix,
 		validateRedisTTL,
+		validateRedisCodec,
+		validateRedisCompressThreshold,
+		validateRedisWarmup,
+		validateLogLevel,
+		validateLogFormat,
+		validateLogSampling,
+		validateLogFile,
+		validateTracing,
+		validateRateLimit,
+		validateGraphQLHandler,
+		validateGraphQLLimits,
+		validateGraphQLPersisted,
+		validateIdempotency,
 	}
 
 	for _, check := range checks {
@@ -95,6 +129,217 @@ func validateEnvironment(cfg *Config) error {
 	return nil
 }
 
+// validateHTTPTimeouts validates the server and route timeouts are not negative
+func validateHTTPTimeouts(cfg *Config) error {
+	timeouts := []struct {
+		name  string
+		value int
+	}{
+		{"HTTP_READ_TIMEOUT", cfg.HTTP.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", cfg.HTTP.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", cfg.HTTP.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", cfg.HTTP.IdleTimeout},
+		{"HTTP_REQUEST_TIMEOUT", cfg.HTTP.RequestTimeout},
+		{"HTTP_ADMIN_TIMEOUT", cfg.HTTP.AdminTimeout},
+		{"HTTP_GRAPHQL_TIMEOUT", cfg.HTTP.GraphQLTimeout},
+	}
+	for _, t := range timeouts {
+		if t.value < 0 {
+			return fmt.Errorf(
+				"invalid %s: %d. Expected value greater than or equal to 0. "+
+					"Set APP_%s environment variable",
+				t.name, t.value, t.name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateHTTPShutdown validates draining and shutdown fit in HTTPMaxStopTime
+func validateHTTPShutdown(cfg *Config) error {
+	if cfg.HTTP.DrainPeriod < 0 || cfg.HTTP.ShutdownTimeout < 0 ||
+		cfg.HTTP.DrainPeriod+cfg.HTTP.ShutdownTimeout > HTTPMaxStopTime {
+		return fmt.Errorf(
+			"invalid HTTP_DRAIN_PERIOD/HTTP_SHUTDOWN_TIMEOUT: %d/%d. Expected values >= 0 adding up to at most %d. "+
+				"Set APP_HTTP_DRAIN_PERIOD and APP_HTTP_SHUTDOWN_TIMEOUT environment variables",
+			cfg.HTTP.DrainPeriod, cfg.HTTP.ShutdownTimeout, HTTPMaxStopTime,
+		)
+	}
+	return nil
+}
+
+// validateHTTPAdmin keeps the admin routes authenticated in production
+func validateHTTPAdmin(cfg *Config) error {
+	if cfg.HTTP.AdminAnonymous && cfg.IsProduction() {
+		return fmt.Errorf(
+			"HTTP_ADMIN_ANONYMOUS cannot be enabled in production, admin routes need an API key or a client certificate. " +
+				"Set APP_HTTP_ADMIN_ANONYMOUS=false",
+		)
+	}
+	return nil
+}
+
+// validateHTTPTLS validates the certificate files exist when TLS is enabled
+func validateHTTPTLS(cfg *Config) error {
+	tlsCfg := cfg.HTTP.TLS
+	if !tlsCfg.Enabled {
+		if tlsCfg.ClientCAFile != "" {
+			return fmt.Errorf(
+				"HTTP_TLS_CLIENT_CA_FILE requires TLS. " +
+					"Set APP_HTTP_TLS_ENABLED=true or unset APP_HTTP_TLS_CLIENT_CA_FILE",
+			)
+		}
+		return nil
+	}
+	if cfg.HTTP.H2C {
+		return fmt.Errorf(
+			"HTTP_H2C only applies to plain HTTP, HTTP/2 is always offered over TLS. " +
+				"Unset APP_HTTP_H2C or APP_HTTP_TLS_ENABLED",
+		)
+	}
+	files := [][2]string{
+		{"HTTP_TLS_CERT_FILE", tlsCfg.CertFile},
+		{"HTTP_TLS_KEY_FILE", tlsCfg.KeyFile},
+	}
+	if tlsCfg.ClientCAFile != "" {
+		files = append(files, [2]string{"HTTP_TLS_CLIENT_CA_FILE", tlsCfg.ClientCAFile})
+	}
+	for _, f := range files {
+		name, path := f[0], f[1]
+		if path == "" {
+			return fmt.Errorf("%s is empty. Set APP_%s environment variable", name, name)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("invalid %s: %v. Set APP_%s environment variable", name, err, name)
+		}
+	}
+	switch tlsCfg.MinVersion {
+	case "", "1.2", "1.3":
+	default:
+		return fmt.Errorf(
+			"invalid HTTP_TLS_MIN_VERSION: %q. Expected one of: 1.2, 1.3. "+
+				"Set APP_HTTP_TLS_MIN_VERSION environment variable",
+			tlsCfg.MinVersion,
+		)
+	}
+	if tlsCfg.ReloadInterval < 0 {
+		return fmt.Errorf(
+			"invalid HTTP_TLS_RELOAD_INTERVAL: %d. Expected value greater than or equal to 0. "+
+				"Set APP_HTTP_TLS_RELOAD_INTERVAL environment variable",
+			tlsCfg.ReloadInterval,
+		)
+	}
+	return nil
+}
+
+// validateHTTPTrustedProxies validates every trusted proxy is an IP or CIDR
+func validateHTTPTrustedProxies(cfg *Config) error {
+	for _, proxy := range cfg.HTTP.TrustedProxies {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			return fmt.Errorf(
+				"invalid HTTP_TRUSTED_PROXIES: %q is neither an IP nor a CIDR. "+
+					"Set APP_HTTP_TRUSTED_PROXIES environment variable",
+				proxy,
+			)
+		}
+	}
+	return nil
+}
+
+// validateHTTPCORS validates allowed origins are bare origins and
+// credentials are not shared with any origin
+func validateHTTPCORS(cfg *Config) error {
+	cors := cfg.HTTP.CORS
+	for _, origin := range cors.AllowedOrigins {
+		if origin == "*" {
+			if cors.AllowCredentials {
+				return fmt.Errorf(
+					"HTTP_CORS_ALLOW_CREDENTIALS cannot be used with the * origin. " +
+						"List the origins in APP_HTTP_CORS_ALLOWED_ORIGINS environment variable",
+				)
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			(u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return fmt.Errorf(
+				"invalid HTTP_CORS_ALLOWED_ORIGINS: %q. Expected scheme://host[:port] or *. "+
+					"Set APP_HTTP_CORS_ALLOWED_ORIGINS environment variable",
+				origin,
+			)
+		}
+	}
+	if cors.MaxAge < 0 {
+		return fmt.Errorf(
+			"invalid HTTP_CORS_MAX_AGE: %d. Expected value greater than or equal to 0. "+
+				"Set APP_HTTP_CORS_MAX_AGE environment variable",
+			cors.MaxAge,
+		)
+	}
+	return nil
+}
+
+// validateHTTPSecurity validates the security header settings
+func validateHTTPSecurity(cfg *Config) error {
+	sec := cfg.HTTP.Security
+	if sec.HSTSMaxAge < 0 {
+		return fmt.Errorf(
+			"invalid HTTP_HSTS_MAX_AGE: %d. Expected value greater than or equal to 0. "+
+				"Set APP_HTTP_HSTS_MAX_AGE environment variable",
+			sec.HSTSMaxAge,
+		)
+	}
+	switch sec.FrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		return fmt.Errorf(
+			"invalid HTTP_FRAME_OPTIONS: %q. Expected one of: DENY, SAMEORIGIN. "+
+				"Set APP_HTTP_FRAME_OPTIONS environment variable",
+			sec.FrameOptions,
+		)
+	}
+	return nil
+}
+
+// validateHTTPCompression validates the compression threshold
+func validateHTTPCompression(cfg *Config) error {
+	if cfg.HTTP.Compression.MinSize < 0 {
+		return fmt.Errorf(
+			"invalid HTTP_COMPRESSION_MIN_SIZE: %d. Expected value greater than or equal to 0. "+
+				"Set APP_HTTP_COMPRESSION_MIN_SIZE environment variable",
+			cfg.HTTP.Compression.MinSize,
+		)
+	}
+	return nil
+}
+
+// validateHTTPCacheControl validates every Cache-Control value is a list
+// of directives such as "public, max-age=60"
+func validateHTTPCacheControl(cfg *Config) error {
+	for _, v := range []struct {
+		name  string
+		value string
+	}{
+		{"HTTP_CACHE_CONTROL_PRODUCTS", cfg.HTTP.CacheControl.Products},
+		{"HTTP_CACHE_CONTROL_PRODUCT", cfg.HTTP.CacheControl.Product},
+	} {
+		for _, directive := range strings.Split(v.value, ",") {
+			if v.value != "" && !cacheDirective.MatchString(strings.TrimSpace(directive)) {
+				return fmt.Errorf(
+					"invalid %s: %q. Expected comma separated directives (e.g., public, max-age=60). "+
+						"Set APP_%s environment variable",
+					v.name, v.value, v.name,
+				)
+			}
+		}
+	}
+	return nil
+}
+
 // validateDatabaseDSN validates database DSN is not empty and valid
 func validateDatabaseDSN(cfg *Config) error {
 	if cfg.Database.DSN == "" {
@@ -119,7 +364,101 @@ func validateDatabaseDSN(cfg *Config) error {
 	return nil
 }
 
-// validateRedisDSN validates Redis DSN is not empty
+// validateDatabaseReplicas validates replica DSNs and the health check interval
+func validateDatabaseReplicas(cfg *Config) error {
+	if len(cfg.Database.ReplicaDSNs) == 0 {
+		return nil
+	}
+	for _, dsn := range cfg.Database.ReplicaDSNs {
+		if !strings.HasPrefix(dsn, "postgresql://") && !strings.HasPrefix(dsn, "postgres://") {
+			return fmt.Errorf(
+				"invalid DATABASE_REPLICA_DSNS format: every replica must start with postgresql:// or postgres://. "+
+					"Provided: %q",
+				dsn,
+			)
+		}
+	}
+	if cfg.Database.ReplicaCheck <= 0 {
+		return fmt.Errorf(
+			"invalid DATABASE_REPLICA_CHECK: %d. Expected value greater than 0 (in seconds). "+
+				"Set APP_DATABASE_REPLICA_CHECK environment variable",
+			cfg.Database.ReplicaCheck,
+		)
+	}
+	return nil
+}
+
+// validateDatabasePool validates database pool sizes and durations are not negative
+func validateDatabasePool(cfg *Config) error {
+	db := cfg.Database
+	values := []struct {
+		name  string
+		value int
+	}{
+		{"DATABASE_MAX_OPEN_CONNS", db.MaxOpenConns},
+		{"DATABASE_MIN_CONNS", db.MinConns},
+		{"DATABASE_CONN_MAX_LIFETIME", db.ConnMaxLifetime},
+		{"DATABASE_CONN_MAX_IDLE_TIME", db.ConnMaxIdleTime},
+		{"DATABASE_STATEMENT_TIMEOUT", db.StatementTimeout},
+		{"DATABASE_TX_MAX_RETRIES", db.TxMaxRetries},
+	}
+	for _, v := range values {
+		if v.value < 0 {
+			return fmt.Errorf(
+				"invalid %s: %d. Expected value greater than or equal to 0. "+
+					"Set APP_%s environment variable",
+				v.name, v.value, v.name,
+			)
+		}
+	}
+
+	if db.MaxOpenConns > 0 && db.MinConns > db.MaxOpenConns {
+		return fmt.Errorf(
+			"invalid DATABASE_MIN_CONNS: %d. Expected value less than or equal to DATABASE_MAX_OPEN_CONNS (%d). "+
+				"Set APP_DATABASE_MIN_CONNS environment variable",
+			db.MinConns, db.MaxOpenConns,
+		)
+	}
+
+	return nil
+}
+
+// validateDatabasePing validates ping retries and backoff when ping on start is enabled
+func validateDatabasePing(cfg *Config) error {
+	if !cfg.Database.PingOnStart {
+		return nil
+	}
+	if cfg.Database.PingRetries < 0 {
+		return fmt.Errorf(
+			"invalid DATABASE_PING_RETRIES: %d. Expected value greater than or equal to 0. "+
+				"Set APP_DATABASE_PING_RETRIES environment variable",
+			cfg.Database.PingRetries,
+		)
+	}
+	if cfg.Database.PingBackoff <= 0 {
+		return fmt.Errorf(
+			"invalid DATABASE_PING_BACKOFF: %d. Expected value greater than 0 (in milliseconds). "+
+				"Set APP_DATABASE_PING_BACKOFF environment variable",
+			cfg.Database.PingBackoff,
+		)
+	}
+	return nil
+}
+
+// validateDatabaseMigrate validates the migration timeout when auto-migrate is enabled
+func validateDatabaseMigrate(cfg *Config) error {
+	if cfg.Database.AutoMigrate && cfg.Database.MigrateTimeout <= 0 {
+		return fmt.Errorf(
+			"invalid DATABASE_MIGRATE_TIMEOUT: %d. Expected value greater than 0 (in seconds) when DATABASE_AUTO_MIGRATE is enabled. "+
+				"Set APP_DATABASE_MIGRATE_TIMEOUT environment variable",
+			cfg.Database.MigrateTimeout,
+		)
+	}
+	return nil
+}
+
+// validateRedisDSN validates Redis DSN is not empty and every address in
+// the comma separated list is either host:port or a redis:// / rediss:// URL
 func validateRedisDSN(cfg *Config) error {
 	if cfg.Redis.DSN == "" {
 		return fmt.Errorf(
@@ -128,16 +467,77 @@ func validateRedisDSN(cfg *Config) error {
 		)
 	}
 
-	// Basic format check (host:port)
-	parts := strings.Split(cfg.Redis.DSN, ":")
-	if len(parts) < 2 {
+	for _, addr := range strings.Split(cfg.Redis.DSN, ",") {
+		addr = strings.TrimSpace(addr)
+		if strings.Contains(addr, "://") {
+			u, err := url.Parse(addr)
+			if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+				return fmt.Errorf(
+					"invalid REDIS_DSN format: expected redis://[user:pass@]host:port[/db] or rediss://... "+
+						"Provided: %q",
+					addr,
+				)
+			}
+			continue
+		}
+
+		// Basic format check (host:port)
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf(
+				"invalid REDIS_DSN format: expected host:port. "+
+					"Provided: %q",
+				addr,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateRedisMode validates Redis mode and the settings each mode requires
+func validateRedisMode(cfg *Config) error {
+	switch cfg.Redis.Mode {
+	case "", RedisModeSingle:
+		if strings.Contains(cfg.Redis.DSN, ",") {
+			return fmt.Errorf(
+				"REDIS_DSN lists several addresses but REDIS_MODE is single. " +
+					"Set APP_REDIS_MODE to sentinel or cluster",
+			)
+		}
+	case RedisModeSentinel:
+		if cfg.Redis.MasterName == "" {
+			return fmt.Errorf(
+				"REDIS_MASTER_NAME is empty. " +
+					"Set APP_REDIS_MASTER_NAME environment variable when APP_REDIS_MODE is sentinel",
+			)
+		}
+	case RedisModeCluster:
+		if cfg.Redis.DB != 0 {
+			return fmt.Errorf(
+				"invalid REDIS_DB: %d. Redis Cluster only supports DB 0. "+
+					"Set APP_REDIS_DB environment variable to 0",
+				cfg.Redis.DB,
+			)
+		}
+	default:
 		return fmt.Errorf(
-			"invalid REDIS_DSN format: expected host:port. "+
-				"Provided: %q",
-			cfg.Redis.DSN,
+			"invalid REDIS_MODE: %q. Expected one of: single, sentinel, cluster. "+
+				"Set APP_REDIS_MODE environment variable",
+			cfg.Redis.Mode,
 		)
 	}
+	return nil
+}
 
+// validateRedisTLS validates the client certificate is configured as a pair
+func validateRedisTLS(cfg *Config) error {
+	tlsCfg := cfg.Redis.TLS
+	if (tlsCfg.CertFile == "") != (tlsCfg.KeyFile == "") {
+		return fmt.Errorf(
+			"REDIS_TLS_CERT_FILE and REDIS_TLS_KEY_FILE must be set together. " +
+				"Set both APP_REDIS_TLS_CERT_FILE and APP_REDIS_TLS_KEY_FILE environment variables",
+		)
+	}
 	return nil
 }
 
@@ -186,8 +586,298 @@ func validateRedisTTL(cfg *Config) error {
 	return nil
 }
 
+// validateRedisCodec validates Redis codec is a supported serialization format
+func validateRedisCodec(cfg *Config) error {
+	switch cfg.Redis.Codec {
+	case "", "json", "msgpack":
+		return nil
+	}
+	return fmt.Errorf(
+		"invalid REDIS_CODEC: %q. Expected one of: json, msgpack. "+
+			"Set APP_REDIS_CODEC environment variable",
+		cfg.Redis.Codec,
+	)
+}
+
+// validateRedisCompressThreshold validates Redis compress threshold is not negative
+func validateRedisCompressThreshold(cfg *Config) error {
+	if cfg.Redis.CompressThreshold < 0 {
+		return fmt.Errorf(
+			"invalid REDIS_COMPRESS_THRESHOLD: %d. Expected 0 (disabled) or a size in bytes. "+
+				"Set APP_REDIS_COMPRESS_THRESHOLD environment variable",
+			cfg.Redis.CompressThreshold,
+		)
+	}
+	return nil
+}
+
+// validateRedisWarmup validates Redis warm-up top N is not negative
+func validateRedisWarmup(cfg *Config) error {
+	if cfg.Redis.WarmupTopN < 0 {
+		return fmt.Errorf(
+			"invalid REDIS_WARMUP_TOP_N: %d. Expected value greater than or equal to 0. "+
+				"Set APP_REDIS_WARMUP_TOP_N environment variable",
+			cfg.Redis.WarmupTopN,
+		)
+	}
+	return nil
+}
+
+// validateLogLevel validates the log level and the per package levels
+func validateLogLevel(cfg *Config) error {
+	if !validLogLevel(cfg.Log.Level) {
+		return fmt.Errorf(
+			"invalid LOG_LEVEL: %q. Expected one of: debug, info, warn, error. "+
+				"Set APP_LOG_LEVEL environment variable",
+			cfg.Log.Level,
+		)
+	}
+	levels, err := cfg.Log.PackageLevels()
+	if err != nil {
+		return fmt.Errorf(
+			"invalid LOG_PACKAGES: %v. Expected a comma separated list of name=level. "+
+				"Set APP_LOG_PACKAGES environment variable",
+			err,
+		)
+	}
+	for name, level := range levels {
+		if !validLogLevel(level) {
+			return fmt.Errorf(
+				"invalid LOG_PACKAGES: level %q of %q. Expected one of: debug, info, warn, error. "+
+					"Set APP_LOG_PACKAGES environment variable",
+				level, name,
+			)
+		}
+	}
+	return nil
+}
+
+func validLogLevel(level string) bool {
+	switch level {
+	case "", "debug", "info", "warn", "error":
+		return true
+	}
+	return false
+}
+
+// validateLogFormat validates the log encoding
+func validateLogFormat(cfg *Config) error {
+	switch cfg.Log.Format {
+	case "", "json", "console":
+		return nil
+	}
+	return fmt.Errorf(
+		"invalid LOG_FORMAT: %q. Expected one of: json, console. "+
+			"Set APP_LOG_FORMAT environment variable",
+		cfg.Log.Format,
+	)
+}
+
+// validateLogSampling validates the sampling settings are not negative
+func validateLogSampling(cfg *Config) error {
+	if cfg.Log.SamplingInitial < 0 || cfg.Log.SamplingThereafter < 0 {
+		return fmt.Errorf(
+			"invalid LOG_SAMPLING_INITIAL/LOG_SAMPLING_THEREAFTER: %d/%d. Expected values >= 0. "+
+				"Set APP_LOG_SAMPLING_INITIAL and APP_LOG_SAMPLING_THEREAFTER environment variables",
+			cfg.Log.SamplingInitial, cfg.Log.SamplingThereafter,
+		)
+	}
+	return nil
+}
+
+// validateLogFile validates the rotation settings of the log file
+func validateLogFile(cfg *Config) error {
+	if cfg.Log.FileMaxSize < 0 || cfg.Log.FileMaxBackups < 0 || cfg.Log.FileMaxAge < 0 {
+		return fmt.Errorf(
+			"invalid LOG_FILE_MAX_SIZE/LOG_FILE_MAX_BACKUPS/LOG_FILE_MAX_AGE: %d/%d/%d. Expected values >= 0. "+
+				"Set APP_LOG_FILE_MAX_SIZE, APP_LOG_FILE_MAX_BACKUPS and APP_LOG_FILE_MAX_AGE environment variables",
+			cfg.Log.FileMaxSize, cfg.Log.FileMaxBackups, cfg.Log.FileMaxAge,
+		)
+	}
+	return nil
+}
+
+// validateTracing validates the exporter and the sample ratio
+func validateTracing(cfg *Config) error {
+	switch cfg.Tracing.Exporter {
+	case "", TracingExporterOTLP, TracingExporterStdout:
+	case TracingExporterFile:
+		if cfg.Tracing.Enabled && cfg.Tracing.File == "" {
+			return fmt.Errorf(
+				"invalid TRACING_FILE: required by the file exporter. " +
+					"Set APP_TRACING_FILE environment variable",
+			)
+		}
+	default:
+		return fmt.Errorf(
+			"invalid TRACING_EXPORTER: %q. Expected one of: otlp, stdout, file. "+
+				"Set APP_TRACING_EXPORTER environment variable",
+			cfg.Tracing.Exporter,
+		)
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		return fmt.Errorf(
+			"invalid TRACING_SAMPLE_RATIO: %v. Expected value between 0 and 1. "+
+				"Set APP_TRACING_SAMPLE_RATIO environment variable",
+			cfg.Tracing.SampleRatio,
+		)
+	}
+	return nil
+}
+
+// validateRateLimit validates rates and bursts are not negative
+func validateRateLimit(cfg *Config) error {
+	rl := cfg.RateLimit
+	for _, v := range []struct {
+		name  string
+		value int
+	}{
+		{"RATE_LIMIT_CLIENT_RATE", rl.ClientRate},
+		{"RATE_LIMIT_CLIENT_BURST", rl.ClientBurst},
+		{"RATE_LIMIT_ADMIN_RATE", rl.AdminRate},
+		{"RATE_LIMIT_ADMIN_BURST", rl.AdminBurst},
+		{"RATE_LIMIT_GRAPHQL_RATE", rl.GraphQLRate},
+		{"RATE_LIMIT_GRAPHQL_BURST", rl.GraphQLBurst},
+	} {
+		if v.value < 0 {
+			return fmt.Errorf(
+				"invalid %s: %d. Expected value greater than or equal to 0. "+
+					"Set APP_%s environment variable",
+				v.name, v.value, v.name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateGraphQLHandler validates the transports and cache settings
+func validateGraphQLHandler(cfg *Config) error {
+	for _, name := range cfg.GraphQL.Transports {
+		switch name {
+		case GraphQLTransportGET, GraphQLTransportPOST, GraphQLTransportMultipart, GraphQLTransportWebSocket:
+		default:
+			return fmt.Errorf(
+				"invalid GRAPHQL_TRANSPORTS: %q. Expected any of: get, post, multipart, websocket. "+
+					"Set APP_GRAPHQL_TRANSPORTS environment variable",
+				name,
+			)
+		}
+	}
+	if cfg.GraphQL.QueryCacheSize < 0 {
+		return fmt.Errorf(
+			"invalid GRAPHQL_QUERY_CACHE_SIZE: %d. Expected value greater than or equal to 0. "+
+				"Set APP_GRAPHQL_QUERY_CACHE_SIZE environment variable",
+			cfg.GraphQL.QueryCacheSize,
+		)
+	}
+	if cfg.GraphQL.WebSocketKeepAlive < 0 {
+		return fmt.Errorf(
+			"invalid GRAPHQL_WEBSOCKET_KEEPALIVE: %d. Expected value greater than or equal to 0. "+
+				"Set APP_GRAPHQL_WEBSOCKET_KEEPALIVE environment variable",
+			cfg.GraphQL.WebSocketKeepAlive,
+		)
+	}
+	return nil
+}
+
+// validateGraphQLLimits validates the operation limits are not negative
+func validateGraphQLLimits(cfg *Config) error {
+	for _, v := range []struct {
+		name  string
+		value int
+	}{
+		{"GRAPHQL_MAX_COMPLEXITY", cfg.GraphQL.MaxComplexity},
+		{"GRAPHQL_MAX_DEPTH", cfg.GraphQL.MaxDepth},
+		{"GRAPHQL_MAX_LIMIT", cfg.GraphQL.MaxLimit},
+	} {
+		if v.value < 0 {
+			return fmt.Errorf(
+				"invalid %s: %d. Expected value greater than or equal to 0. "+
+					"Set APP_%s environment variable",
+				v.name, v.value, v.name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateGraphQLPersisted validates the APQ TTL and the trusted documents
+// manifest exists when it is required
+func validateGraphQLPersisted(cfg *Config) error {
+	gc := cfg.GraphQL
+	if gc.APQTTL < 0 {
+		return fmt.Errorf(
+			"invalid GRAPHQL_APQ_TTL: %d. Expected value greater than or equal to 0. "+
+				"Set APP_GRAPHQL_APQ_TTL environment variable",
+			gc.APQTTL,
+		)
+	}
+	if gc.TrustedOnly && gc.TrustedDocuments == "" {
+		return fmt.Errorf(
+			"GRAPHQL_TRUSTED_ONLY requires a manifest. " +
+				"Set APP_GRAPHQL_TRUSTED_DOCUMENTS environment variable",
+		)
+	}
+	if gc.TrustedDocuments != "" {
+		if _, err := os.Stat(gc.TrustedDocuments); err != nil {
+			return fmt.Errorf(
+				"invalid GRAPHQL_TRUSTED_DOCUMENTS: %v. Set APP_GRAPHQL_TRUSTED_DOCUMENTS environment variable",
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// validateIdempotency validates the replay window and the lock outlives
+// the admin requests it guards
+func validateIdempotency(cfg *Config) error {
+	ic := cfg.Idempotency
+	for _, v := range []struct {
+		name  string
+		value int
+	}{
+		{"IDEMPOTENCY_TTL", ic.TTL},
+		{"IDEMPOTENCY_LOCK_TIMEOUT", ic.LockTimeout},
+	} {
+		if v.value < 0 || (ic.Enabled && v.value == 0) {
+			return fmt.Errorf(
+				"invalid %s: %d. Expected value greater than 0 when IDEMPOTENCY_ENABLED is set. "+
+					"Set APP_%s environment variable",
+				v.name, v.value, v.name,
+			)
+		}
+	}
+	if ic.Enabled && cfg.HTTP.AdminTimeout > 0 && ic.LockTimeout < cfg.HTTP.AdminTimeout {
+		return fmt.Errorf(
+			"invalid IDEMPOTENCY_LOCK_TIMEOUT: %d. Expected value greater than or equal to HTTP_ADMIN_TIMEOUT (%d), "+
+				"retries would run while the first request is still in progress. "+
+				"Set APP_IDEMPOTENCY_LOCK_TIMEOUT environment variable",
+			ic.LockTimeout, cfg.HTTP.AdminTimeout,
+		)
+	}
+	return nil
+}
+
 // validateWarnings logs non-critical warnings for configuration
 func validateWarnings(cfg *Config) {
+	// Warn about responses cut by the write timeout before the route timeout
+	routeTimeout := max(cfg.HTTP.RequestTimeout, cfg.HTTP.AdminTimeout, cfg.HTTP.GraphQLTimeout)
+	if cfg.HTTP.WriteTimeout > 0 && cfg.HTTP.WriteTimeout <= routeTimeout {
+		log.Printf("⚠️  WARNING: HTTP_WRITE_TIMEOUT (%ds) is not above the longest route timeout (%ds), "+
+			"timed out requests get no response. Raise APP_HTTP_WRITE_TIMEOUT\n", cfg.HTTP.WriteTimeout, routeTimeout)
+	}
+
+	// Warn about queries allowed by the complexity limit but never by the rate limit
+	burst := cfg.RateLimit.GraphQLBurst
+	if burst == 0 {
+		burst = cfg.RateLimit.GraphQLRate
+	}
+	if cfg.RateLimit.Enabled && burst > 0 && cfg.GraphQL.MaxComplexity > burst {
+		log.Printf("⚠️  WARNING: GRAPHQL_MAX_COMPLEXITY (%d) exceeds the GraphQL rate limit burst (%d), "+
+			"costlier queries are always rejected. Lower APP_GRAPHQL_MAX_COMPLEXITY\n", cfg.GraphQL.MaxComplexity, burst)
+	}
+
 	// Warn about default JWT secret in production
 	if cfg.IsProduction() {
 
@@ -208,6 +898,18 @@ func validateWarnings(cfg *Config) {
 			log.Printf("⚠️  WARNING: REDIS_DSN points to localhost in production. " +
 				"Set APP_REDIS_DSN to production Redis server\n")
 		}
+
+		// Warn about skipping Redis certificate verification in production
+		if cfg.Redis.TLS.InsecureSkipVerify {
+			log.Printf("⚠️  WARNING: REDIS_TLS_INSECURE_SKIP_VERIFY is enabled in production. " +
+				"Set APP_REDIS_TLS_CA_FILE instead\n")
+		}
+
+		// Warn about debug logs in production
+		if cfg.Log.Level == "debug" {
+			log.Printf("⚠️  WARNING: LOG_LEVEL is debug in production. " +
+				"Set APP_LOG_LEVEL to info or change it at runtime instead\n")
+		}
 	}
 
 }
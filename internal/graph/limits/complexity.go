@@ -0,0 +1,26 @@
+package limits
+
+import (
+	"go-graphql/internal/graph/generated"
+	"go-graphql/internal/graph/model"
+)
+
+// Complexity prices list fields by the page they ask for, every item
+// costs its whole selection, limits above maxLimit are priced at maxLimit
+// since the resolver rejects them anyway. Scalar fields keep gqlgen's
+// default cost of 1
+func Complexity(maxLimit int) generated.ComplexityRoot {
+	var c generated.ComplexityRoot
+	c.Query.Products = func(childComplexity int, _ *model.ProductFilter, pagination *model.PaginationInput) int {
+		return 1 + pageSize(pagination, maxLimit)*childComplexity
+	}
+	return c
+}
+
+func pageSize(pagination *model.PaginationInput, maxLimit int) int {
+	limit := max(pagination.PageLimit(), 0)
+	if maxLimit > 0 {
+		limit = min(limit, maxLimit)
+	}
+	return limit
+}
@@ -0,0 +1,82 @@
+package limits
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// CodeDepthLimit is set in the extensions of the error returned for too
+// deeply nested operations
+const CodeDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// DepthLimit is a gqlgen extension rejecting operations nesting selection
+// sets deeper than a limit, fragments count at the depth they are spread
+type DepthLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = DepthLimit{}
+
+// NewDepthLimit returns the extension to Use on the gqlgen handler
+func NewDepthLimit(maxDepth int) DepthLimit {
+	return DepthLimit{max: maxDepth}
+}
+
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (DepthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(_ context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil {
+		return nil
+	}
+	if depth := selectionDepth(opCtx.Operation.SelectionSet, d.max+1); depth > d.max {
+		err := gqlerror.Errorf("operation exceeds the depth limit of %d", d.max)
+		errcode.Set(err, CodeDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns the depth of the deepest field, it stops at
+// stop so cyclic or huge documents are not walked to the end.
+// Introspection fields are skipped, clients nest them deeply to read types
+func selectionDepth(set ast.SelectionSet, stop int) int {
+	depth := 0
+	for _, selection := range set {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1
+			if len(s.SelectionSet) > 0 && stop > 1 {
+				d += selectionDepth(s.SelectionSet, stop-1)
+			}
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet, stop)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = selectionDepth(s.Definition.SelectionSet, stop)
+			}
+		}
+		depth = max(depth, d)
+		if depth >= stop {
+			return depth
+		}
+	}
+	return depth
+}
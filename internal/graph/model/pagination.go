@@ -0,0 +1,20 @@
+package model
+
+// DefaultLimit is the page size when PaginationInput.limit is omitted
+const DefaultLimit = 10
+
+// PageLimit returns the requested page size or DefaultLimit
+func (p *PaginationInput) PageLimit() int {
+	if p == nil || p.Limit == nil {
+		return DefaultLimit
+	}
+	return *p.Limit
+}
+
+// PageOffset returns the requested offset or 0
+func (p *PaginationInput) PageOffset() int {
+	if p == nil || p.Offset == nil {
+		return 0
+	}
+	return *p.Offset
+}
@@ -0,0 +1,49 @@
+package persisted
+
+import (
+	"context"
+	"errors"
+
+	"go-graphql/internal/config"
+	"go-graphql/internal/pkg/logger"
+	"go-graphql/internal/storage/cache"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// QueryCache stores automatic persisted queries in Redis so a hash
+// registered through one instance is known to all of them
+type QueryCache struct {
+	store *cache.Store
+	ttl   int
+	log   *zap.Logger
+}
+
+var _ graphql.Cache[string] = (*QueryCache)(nil)
+
+func NewQueryCache(store *cache.Store, cfg *config.Config, log *zap.Logger) *QueryCache {
+	return &QueryCache{store: store, ttl: cfg.GraphQL.APQTTL, log: log.Named("apq")}
+}
+
+// Get returns the query registered under hash, Redis errors count as a
+// miss so the client falls back to sending the full query
+func (c *QueryCache) Get(ctx context.Context, hash string) (string, bool) {
+	var query string
+	err := c.store.Get(ctx, c.store.KeyPersistedQuery(hash), &query)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			logger.FromContext(ctx, c.log).Warn("Failed to read persisted query", zap.String("hash", hash), zap.Error(err))
+		}
+		return "", false
+	}
+	return query, true
+}
+
+// Add registers query under hash, gqlgen checked the hash matches
+func (c *QueryCache) Add(ctx context.Context, hash string, query string) {
+	if err := c.store.Set(ctx, c.store.KeyPersistedQuery(hash), query, c.ttl); err != nil {
+		logger.FromContext(ctx, c.log).Warn("Failed to store persisted query", zap.String("hash", hash), zap.Error(err))
+	}
+}
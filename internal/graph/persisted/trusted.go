@@ -0,0 +1,144 @@
+package persisted
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go-graphql/internal/config"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Error codes set in the extensions of rejected operations
+const (
+	CodeNotFound   = "PERSISTED_QUERY_NOT_FOUND"
+	CodeNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+)
+
+// Manifest maps the sha256 hash of each trusted document to the document
+type Manifest map[string]string
+
+// NewManifest loads GraphQL.TrustedDocuments, the manifest is nil when
+// none is configured
+func NewManifest(cfg *config.Config) (Manifest, error) {
+	if cfg.GraphQL.TrustedDocuments == "" {
+		return nil, nil
+	}
+	return LoadManifest(cfg.GraphQL.TrustedDocuments)
+}
+
+// LoadManifest reads a JSON object of hash to document and checks every
+// hash matches its document
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read trusted documents: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse trusted documents %s: %w", path, err)
+	}
+	for hash, document := range m {
+		if Hash(document) != hash {
+			return nil, fmt.Errorf("trusted documents %s: hash %s does not match its document", path, hash)
+		}
+	}
+	return m, nil
+}
+
+// OperationNames returns the names of the operations of the trusted
+// documents, documents that do not parse are skipped
+func (m Manifest) OperationNames() []string {
+	var names []string
+	for _, document := range m {
+		doc, err := parser.ParseQuery(&ast.Source{Input: document})
+		if err != nil {
+			continue
+		}
+		for _, op := range doc.Operations {
+			if op.Name != "" {
+				names = append(names, op.Name)
+			}
+		}
+	}
+	return names
+}
+
+// Hash returns the hex sha256 clients send as persistedQuery.sha256Hash
+func Hash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Trusted is a gqlgen extension resolving the hashes of the manifest into
+// their documents. When only is set, operations missing from the manifest
+// are rejected, whether sent as a hash or as a full query
+type Trusted struct {
+	manifest Manifest
+	only     bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = Trusted{}
+
+// NewTrusted returns the extension to Use on the gqlgen handler, it must
+// come before the APQ extension
+func NewTrusted(manifest Manifest, only bool) Trusted {
+	return Trusted{manifest: manifest, only: only}
+}
+
+func (Trusted) ExtensionName() string {
+	return "TrustedDocuments"
+}
+
+func (Trusted) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (t Trusted) MutateOperationParameters(_ context.Context, params *graphql.RawParams) *gqlerror.Error {
+	if params.Query != "" {
+		if t.only && t.manifest[Hash(params.Query)] == "" {
+			return codeErr(CodeNotAllowed, "operation is not a trusted document")
+		}
+		return nil
+	}
+	hash := persistedHash(params)
+	if hash == "" {
+		return nil
+	}
+
+	document, ok := t.manifest[hash]
+	switch {
+	case ok:
+		// the query is known, APQ must not register it again
+		params.Query = document
+		delete(params.Extensions, "persistedQuery")
+	case t.only:
+		return codeErr(CodeNotFound, "persisted query %s is not a trusted document", hash)
+	}
+	return nil
+}
+
+// persistedHash returns the hash of the APQ extension, gqlgen's APQ
+// extension reports malformed ones
+func persistedHash(params *graphql.RawParams) string {
+	ext, _ := params.Extensions["persistedQuery"].(map[string]any)
+	hash, _ := ext["sha256Hash"].(string)
+	return hash
+}
+
+func codeErr(code, format string, args ...any) *gqlerror.Error {
+	err := gqlerror.Errorf(format, args...)
+	errcode.Set(err, code)
+	return err
+}
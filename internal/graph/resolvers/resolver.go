@@ -7,6 +7,10 @@ package resolvers
 
 import product "go-graphql/internal/product/service"
 
+// CodeBadUserInput is set in the extensions of errors caused by invalid
+// arguments
+const CodeBadUserInput = "BAD_USER_INPUT"
+
 type Resolver struct {
 	ProductService *product.Product
 }
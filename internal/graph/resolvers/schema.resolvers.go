@@ -7,14 +7,24 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go-graphql/internal/graph/generated"
 	"go-graphql/internal/graph/model"
+	product "go-graphql/internal/product/service"
+
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 // Products is the resolver for the products field.
 func (r *queryResolver) Products(ctx context.Context, filter *model.ProductFilter, pagination *model.PaginationInput) (*model.ProductConnection, error) {
 	products, err := r.ProductService.ListProducts(ctx, filter, pagination)
+	if errors.Is(err, product.ErrInvalidPagination) {
+		gqlErr := gqlerror.Errorf("%s", err)
+		errcode.Set(gqlErr, CodeBadUserInput)
+		return nil, gqlErr
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to list products: %w", err)
 	}
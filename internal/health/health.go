@@ -1,16 +1,54 @@
 package health
 
 import (
+	"context"
 	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	storage "go-graphql/internal/storage/sql"
+	"go-graphql/internal/storage/sql/migrate"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// checkTimeout bounds every dependency check, a probe must answer well
+// before the orchestrator gives up on it
+const checkTimeout = 2 * time.Second
+
+// Check statuses, a degraded dependency does not fail readiness
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// Readiness statuses
+const (
+	StatusReady       = "ready"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
 )
 
 type Health struct {
+	db         *pgxpool.Pool
+	redis      redis.UniversalClient
+	router     *storage.Router
+	migrations *migrate.Readiness
+	draining   atomic.Bool
+}
+
+func New(db *pgxpool.Pool, redis redis.UniversalClient, router *storage.Router, migrations *migrate.Readiness) *Health {
+	return &Health{db: db, redis: redis, router: router, migrations: migrations}
 }
 
-func New() *Health {
-	return &Health{}
+// Drain makes readiness fail from now on, so load balancers stop sending
+// traffic while in-flight requests complete
+func (h *Health) Drain() {
+	h.draining.Store(true)
 }
 
 // Health godoc
@@ -24,6 +62,125 @@ func (h *Health) Handle(c *gin.Context) {
 	c.JSON(http.StatusOK, HealthResponse{Message: "OK"})
 }
 
+// Live godoc
+// @Summary Get liveness status
+// @Description Returns 200 while the process serves HTTP, dependencies are not checked so an outage does not restart every instance
+// @Tags Health
+// @Produce json
+// @Success 200 {object} HealthResponse "OK"
+// @Router /health/live [get]
+func (h *Health) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Message: "OK"})
+}
+
+// Ready godoc
+// @Summary Get readiness status
+// @Description Pings the database and Redis and checks the schema is migrated. Returns 503 when one of them is down or the server is draining.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} ReadyResponse
+// @Failure 503 {object} ReadyResponse
+// @Router /health/ready [get]
+func (h *Health) Ready(c *gin.Context) {
+	resp := h.check(c.Request.Context())
+	status := http.StatusOK
+	if resp.Status != StatusReady {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
+
+// check runs the dependency checks concurrently
+func (h *Health) check(ctx context.Context) ReadyResponse {
+	checks := map[string]func(context.Context) error{
+		"database": h.db.Ping,
+		"redis": func(ctx context.Context) error {
+			return h.redis.Ping(ctx).Err()
+		},
+		"migrations": func(context.Context) error {
+			if ready, err := h.migrations.Ready(); !ready {
+				return err
+			}
+			return nil
+		},
+	}
+
+	resp := ReadyResponse{Status: StatusReady, Checks: make(map[string]CheckResult, len(checks)+1)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, fn := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := runCheck(ctx, fn)
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[name] = result
+			if result.Status == StatusDown {
+				resp.Status = StatusUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+
+	if replicas := h.router.Replicas(); len(replicas) > 0 {
+		resp.Checks["replicas"] = replicasResult(replicas)
+	}
+	if h.draining.Load() {
+		resp.Status = StatusDraining
+	}
+	return resp
+}
+
+func runCheck(ctx context.Context, fn func(context.Context) error) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	start := time.Now()
+	err := fn(ctx)
+	result := CheckResult{Status: StatusUp, LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status, result.Error = StatusDown, err.Error()
+	}
+	return result
+}
+
+// replicasResult reports the last replica health checks, reads fall back
+// to the primary so unhealthy replicas only degrade the instance
+func replicasResult(replicas []storage.ReplicaStatus) CheckResult {
+	result := CheckResult{Status: StatusUp, Replicas: replicas}
+	for _, replica := range replicas {
+		if !replica.Healthy {
+			result.Status = StatusDegraded
+		}
+	}
+	return result
+}
+
+// DBStats godoc
+// @Summary Database pool statistics
+// @Description Returns the connection pool statistics of the primary database
+// @Tags Health
+// @Produce json
+// @Success 200 {object} storage.PoolStats
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/db/stats [get]
+func (h *Health) DBStats(c *gin.Context) {
+	c.JSON(http.StatusOK, storage.NewPoolStats(h.db))
+}
+
 type HealthResponse struct {
 	Message string `json:"message" example:"OK"`
 }
+
+type ReadyResponse struct {
+	Status string                 `json:"status" example:"ready"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	Status    string                  `json:"status" example:"up"`
+	LatencyMs float64                 `json:"latencyMs,omitempty" example:"1.2"`
+	Error     string                  `json:"error,omitempty"`
+	Replicas  []storage.ReplicaStatus `json:"replicas,omitempty"`
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl sets the Cache-Control of reads from the policy of their
+// route, keyed by route pattern such as /api/v1/products/:id. Only 200
+// and 304 answers are cacheable, other statuses get no-store so shared
+// caches never keep an error
+func CacheControl(policies map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := policies[c.FullPath()]
+		if policy == "" || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+			c.Next()
+			return
+		}
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, policy: policy}
+		c.Next()
+	}
+}
+
+// cacheControlWriter picks the header from the status until it is sent
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	policy string
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	w.setHeader(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.setHeader(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	w.setHeader(w.Status())
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.setHeader(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *cacheControlWriter) setHeader(code int) {
+	if w.Written() {
+		return
+	}
+	switch code {
+	case http.StatusOK, http.StatusNotModified:
+		w.Header().Set("Cache-Control", w.policy)
+	default:
+		w.Header().Set("Cache-Control", "no-store")
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"strconv"
+
+	storage "go-graphql/internal/storage/sql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadPrimaryHeader lets clients that just performed a mutation ask for
+// their next reads to be served by the primary database
+const ReadPrimaryHeader = "X-Read-Primary"
+
+// ReadYourWrites prepares the request context for replica routing: reads go
+// to the primary when the client sends X-Read-Primary: true, or once the
+// request itself committed a write
+func ReadYourWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		primary, _ := strconv.ParseBool(c.GetHeader(ReadPrimaryHeader))
+		c.Request = c.Request.WithContext(storage.WithPrimary(c.Request.Context(), primary))
+		c.Next()
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"go-graphql/internal/http/response"
+	"go-graphql/internal/idempotency"
+	"go-graphql/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Idempotency headers, IdempotentReplayedHeader marks replayed responses
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLen bounds the keys clients may send, UUIDs fit easily
+const maxIdempotencyKeyLen = 255
+
+var (
+	errIdempotencyKeyTooLong  = errors.New("Idempotency-Key must be at most 255 characters")
+	errIdempotencyUnavailable = errors.New("idempotency store unavailable, retry later")
+)
+
+// Idempotency replays the stored response to retries of a write sending
+// the same Idempotency-Key, reusing a key for another method, path or body
+// gets a 409. Requests without the header, and safe methods, run as usual
+func Idempotency(s *idempotency.Store) gin.HandlerFunc {
+	if !s.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isWrite(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			response.JSONError(c, http.StatusBadRequest, errIdempotencyKeyTooLong)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.JSONError(c, http.StatusBadRequest, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// JSON formatting changes are not a different request
+		canonical := new(bytes.Buffer)
+		if json.Compact(canonical, body) != nil {
+			canonical = bytes.NewBuffer(body)
+		}
+		fingerprint := idempotency.Fingerprint(c.Request.Method, c.Request.URL.Path, canonical.Bytes())
+		// keys are per authenticated caller, a shared space otherwise
+		scope := ratelimit.Subject(c.Request.Context())
+		if scope == "" {
+			scope = "anonymous"
+		}
+
+		lock, stored, err := s.Begin(c.Request.Context(), scope, key, fingerprint)
+		switch {
+		case errors.Is(err, idempotency.ErrMismatch):
+			response.JSONError(c, http.StatusConflict, err)
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			c.Header("Retry-After", "1")
+			response.JSONError(c, http.StatusConflict, err)
+			return
+		case err != nil:
+			response.JSONError(c, http.StatusServiceUnavailable, errIdempotencyUnavailable)
+			return
+		case stored != nil:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+
+		// the request context is canceled once the route times out, the
+		// handler may still have completed
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := w.Status()
+		if status >= http.StatusInternalServerError {
+			// nothing worth replaying, let the client retry
+			s.Abort(ctx, lock)
+			return
+		}
+		s.Complete(ctx, lock, fingerprint, idempotency.Response{
+			Status:      status,
+			ContentType: w.Header().Get("Content-Type"),
+			Body:        w.body.Bytes(),
+		})
+	}
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// captureWriter keeps a copy of the body written to the client
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
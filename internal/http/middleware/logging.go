@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"go-graphql/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader carries the request id, a valid incoming value is kept so
+// a request can be followed across services
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// RequestID reuses or generates the X-Request-ID, echoes it in the response
+// and attaches a logger tagged with it to the request context
+func RequestID(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, id)
+
+		ctx := logger.WithRequestID(c.Request.Context(), id)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx, log))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// validRequestID accepts short printable ASCII ids, anything else could
+// be used to inject content into the logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestLogger logs one entry per request once it is served, the level
+// follows the status: error for 5xx, warn for 4xx, info otherwise
+func RequestLogger(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", c.Writer.Size()),
+			zap.String("client_ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		reqLog := logger.FromContext(c.Request.Context(), log)
+		switch {
+		case status >= http.StatusInternalServerError:
+			reqLog.Error("Request served", fields...)
+		case status >= http.StatusBadRequest:
+			reqLog.Warn("Request served", fields...)
+		default:
+			reqLog.Info("Request served", fields...)
+		}
+	}
+}
+
+// Recovery turns panics into a 500 logged through zap with the stack trace
+func Recovery(log *zap.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, err any) {
+		logger.FromContext(c.Request.Context(), log).Error("Panic recovered",
+			zap.Any("panic", err),
+			zap.Stack("stack"),
+		)
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"go-graphql/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// standardMethods are the methods kept as labels, any other method sent by
+// a client is recorded as "other"
+var standardMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true,
+	http.MethodPut: true, http.MethodPatch: true, http.MethodDelete: true,
+	http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// Metrics records the rate, errors and duration of requests by route
+// template, unknown paths share the "unmatched" route and unknown methods
+// the "other" method
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		m.HTTPStarted()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		if !standardMethods[method] {
+			method = "other"
+		}
+		m.HTTPDone(method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go-graphql/internal/http/response"
+	"go-graphql/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errRateLimited          = errors.New("rate limit exceeded")
+	errRateLimitUnavailable = errors.New("rate limit unavailable, retry later")
+)
+
+// RateLimit charges one token per request to the caller's bucket of the
+// route group and answers 429 with Retry-After once it is empty, every
+// response carries the RateLimit-* headers of the bucket
+func RateLimit(l *ratelimit.Limiter, group string) gin.HandlerFunc {
+	p := l.Policy(group)
+	if !p.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		res, err := l.Allow(ctx, p, ratelimit.ClientKey(ctx, c.ClientIP()), 1)
+		if err != nil {
+			response.JSONError(c, http.StatusServiceUnavailable, errRateLimitUnavailable)
+			return
+		}
+		setRateLimitHeaders(c.Writer.Header(), p, res)
+		if !res.Allowed {
+			response.JSONError(c, http.StatusTooManyRequests, errRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// GraphQLRateLimit lets the ratelimit.GraphQL extension charge the
+// caller's bucket by query cost, operations it rejects are answered with
+// 429 instead of gqlgen's 200
+func GraphQLRateLimit(l *ratelimit.Limiter) gin.HandlerFunc {
+	p := l.Policy(ratelimit.GroupGraphQL)
+	if !p.Enabled() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		ctx, last := ratelimit.WithClient(ctx, ratelimit.ClientKey(ctx, c.ClientIP()))
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &rateLimitWriter{ResponseWriter: c.Writer, policy: p, last: last}
+		c.Next()
+	}
+}
+
+// rateLimitWriter adds the headers of the last charge when the status is
+// written, WebSocket upgrades hijack the embedded writer untouched
+type rateLimitWriter struct {
+	gin.ResponseWriter
+	policy      ratelimit.Policy
+	last        func() (ratelimit.Result, bool)
+	wroteHeader bool
+}
+
+func (w *rateLimitWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if res, ok := w.last(); ok {
+		setRateLimitHeaders(w.Header(), w.policy, res)
+		if !res.Allowed {
+			code = http.StatusTooManyRequests
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *rateLimitWriter) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *rateLimitWriter) WriteString(s string) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// setRateLimitHeaders follows the IETF RateLimit header fields draft,
+// nothing is set when the bucket state is unknown
+func setRateLimitHeaders(h http.Header, p ratelimit.Policy, res ratelimit.Result) {
+	if res.Limit == 0 {
+		return
+	}
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(ratelimit.Seconds(res.Reset)))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Burst, ratelimit.Seconds(p.Window())))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(ratelimit.Seconds(res.RetryAfter)))
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"go-graphql/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHeaders sets the security headers of every response, paths
+// under docsPaths get the docs Content-Security-Policy which lets the
+// playground and swagger pages load their scripts
+func SecurityHeaders(cfg config.SecurityCfg, docsPaths ...string) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		// browsers ignore HSTS over plain HTTP, behind a TLS-terminating
+		// proxy the request arrives unencrypted but the client used HTTPS
+		if hsts != "" {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+
+		csp := cfg.ContentSecurityPolicy
+		for _, prefix := range docsPaths {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				csp = cfg.DocsContentSecurityPolicy
+				break
+			}
+		}
+		if csp != "" {
+			h.Set("Content-Security-Policy", csp)
+		}
+		c.Next()
+	}
+}
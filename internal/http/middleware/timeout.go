@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go-graphql/internal/http/response"
+
+	"github.com/gin-contrib/timeout"
+	"github.com/gin-gonic/gin"
+)
+
+var errRequestTimeout = errors.New("request timed out")
+
+// Timeout answers 503 once d elapses and cancels the request context, so
+// the queries of an abandoned handler stop as well. d <= 0 disables it,
+// WebSocket upgrades are never timed out.
+func Timeout(d time.Duration) gin.HandlerFunc {
+	if d <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	handler := timeout.New(
+		timeout.WithTimeout(d),
+		timeout.WithResponse(func(c *gin.Context) {
+			response.JSONError(c, http.StatusServiceUnavailable, errRequestTimeout)
+		}),
+	)
+	return func(c *gin.Context) {
+		if c.IsWebsocket() {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		handler(c)
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing opens a server span per request, continuing the trace of the
+// caller when a W3C traceparent header is sent. Probes and metric scrapes
+// are not traced.
+func Tracing(serviceName string, tp trace.TracerProvider) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName,
+		otelgin.WithTracerProvider(tp),
+		otelgin.WithPropagators(otel.GetTextMapPropagator()),
+		otelgin.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/metrics" && !strings.HasPrefix(r.URL.Path, "/health")
+		}),
+	)
+}
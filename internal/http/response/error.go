@@ -1,33 +1,32 @@
 package response
 
 import (
-	"encoding/json"
-	"log"
+	"net/http"
+
+	"go-graphql/internal/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
-type ErrorLog struct {
-	Status int    `json:"status"`
-	Error  string `json:"error"`
-	Path   string `json:"path"`
-	Method string `json:"method"`
-}
-
 func JSONError(ctx *gin.Context, status int, err error) {
-	// Log error as JSON
-	logEntry := ErrorLog{
-		Status: status,
-		Error:  err.Error(),
-		Path:   ctx.Request.URL.Path,
-		Method: ctx.Request.Method,
+	// the request logger already carries the request id
+	log := logger.Ctx(ctx.Request.Context())
+	fields := []zap.Field{
+		zap.Int("status", status),
+		zap.Error(err),
+		zap.String("path", ctx.Request.URL.Path),
+		zap.String("method", ctx.Request.Method),
+	}
+	if status >= http.StatusInternalServerError {
+		log.Error("Request failed", fields...)
+	} else {
+		log.Warn("Request failed", fields...)
 	}
-	logJSON, _ := json.Marshal(logEntry)
-	log.Println(string(logJSON))
 	// Respond to client
 	ctx.AbortWithStatusJSON(status, ErrorResponse{
 		Error: err.Error(),
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EncodedETag returns the ETag of the compressed representation, strong
+// ETags must differ between content codings
+func EncodedETag(etag, encoding string) string {
+	if encoding == "" || !strings.HasSuffix(etag, `"`) || strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + "-" + encoding + `"`
+}
+
+// NotModified sets the ETag of the response and answers 304 when
+// If-None-Match holds it, in any content coding. Handlers return without
+// writing a body when it reports true
+func NotModified(ctx *gin.Context, etag string) bool {
+	ctx.Header("ETag", etag)
+	if !matchETag(ctx.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+	ctx.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// matchETag uses the weak comparison If-None-Match calls for
+func matchETag(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	opaque := strings.TrimSuffix(strings.TrimPrefix(etag, "W/"), `"`)
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(tag), "W/"), `"`)
+		if tag == opaque {
+			return true
+		}
+		if coding, ok := strings.CutPrefix(tag, opaque+"-"); ok && (coding == "gzip" || coding == "br") {
+			return true
+		}
+	}
+	return false
+}
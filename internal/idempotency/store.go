@@ -0,0 +1,163 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-graphql/internal/config"
+	"go-graphql/internal/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrMismatch is returned when a key is reused for another request
+	ErrMismatch = errors.New("Idempotency-Key was already used for a different request")
+	// ErrInProgress is returned while the first request of a key runs
+	ErrInProgress = errors.New("a request with this Idempotency-Key is still in progress")
+)
+
+// Response is the stored answer replayed to the retries of a request
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// record is kept under each key, Response is nil while the first request
+// runs. Token tells the lock of a request apart from a later one taken
+// once the first expired
+type record struct {
+	Fingerprint string    `json:"fingerprint"`
+	Token       string    `json:"token,omitempty"`
+	Response    *Response `json:"response,omitempty"`
+}
+
+// release replaces or deletes the record only while it still holds the
+// lock of the caller
+var release = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+if ARGV[2] == '' then
+  redis.call('DEL', KEYS[1])
+else
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+end
+return 1
+`)
+
+// Store keeps the fingerprint and response of idempotent requests in
+// Redis so retries reaching any instance are answered the same
+type Store struct {
+	client  redis.UniversalClient
+	prefix  string
+	enabled bool
+	ttl     time.Duration
+	lockTTL time.Duration
+	log     *zap.Logger
+}
+
+func New(cfg *config.Config, client redis.UniversalClient, log *zap.Logger) *Store {
+	return &Store{
+		client:  client,
+		prefix:  cfg.Redis.Prefix + ":idempotency",
+		enabled: cfg.Idempotency.Enabled,
+		ttl:     time.Duration(cfg.Idempotency.TTL) * time.Minute,
+		lockTTL: time.Duration(cfg.Idempotency.LockTimeout) * time.Second,
+		log:     log.Named("idempotency"),
+	}
+}
+
+// Enabled reports whether Idempotency-Key headers are honoured
+func (s *Store) Enabled() bool {
+	return s.enabled
+}
+
+// Lock is held by the first request of a key until it completes
+type Lock struct {
+	key   string
+	value string
+}
+
+// Begin claims key for the request identified by fingerprint. The lock is
+// returned to the first request, its retries get the stored response,
+// ErrInProgress while it runs or ErrMismatch for another fingerprint
+func (s *Store) Begin(ctx context.Context, scope, key, fingerprint string) (*Lock, *Response, error) {
+	redisKey := s.key(scope, key)
+	value, err := json.Marshal(record{Fingerprint: fingerprint, Token: rand.Text()})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the record may expire between SET NX and GET, claim it again then
+	for range 2 {
+		ok, err := s.client.SetNX(ctx, redisKey, value, s.lockTTL).Result()
+		if err != nil {
+			return nil, nil, fmt.Errorf("claim idempotency key: %w", err)
+		}
+		if ok {
+			return &Lock{key: redisKey, value: string(value)}, nil, nil
+		}
+
+		data, err := s.client.Get(ctx, redisKey).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read idempotency key: %w", err)
+		}
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, nil, fmt.Errorf("decode idempotency key: %w", err)
+		}
+		switch {
+		case rec.Fingerprint != fingerprint:
+			return nil, nil, ErrMismatch
+		case rec.Response == nil:
+			return nil, nil, ErrInProgress
+		}
+		return nil, rec.Response, nil
+	}
+	return nil, nil, ErrInProgress
+}
+
+// Complete stores the response replayed to retries for the TTL window
+func (s *Store) Complete(ctx context.Context, lock *Lock, fingerprint string, resp Response) {
+	value, err := json.Marshal(record{Fingerprint: fingerprint, Response: &resp})
+	if err == nil {
+		err = release.Run(ctx, s.client, []string{lock.key}, lock.value, value, s.ttl.Milliseconds()).Err()
+	}
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warn("Failed to store idempotent response", zap.Error(err))
+	}
+}
+
+// Abort frees the key so the request can be retried, used when it failed
+// without a response worth replaying
+func (s *Store) Abort(ctx context.Context, lock *Lock) {
+	if err := release.Run(ctx, s.client, []string{lock.key}, lock.value, "", 0).Err(); err != nil {
+		logger.FromContext(ctx, s.log).Warn("Failed to release idempotency key", zap.Error(err))
+	}
+}
+
+// key hashes the client supplied key, it may be long or contain any byte
+func (s *Store) key(scope, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return s.prefix + ":" + scope + ":" + hex.EncodeToString(sum[:])
+}
+
+// Fingerprint identifies a request by its method, path and body
+func Fingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
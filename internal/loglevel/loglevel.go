@@ -0,0 +1,95 @@
+package loglevel
+
+import (
+	"errors"
+	"net/http"
+
+	"go-graphql/internal/http/response"
+	"go-graphql/internal/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var errMissingLevel = errors.New("level is required when no package is given")
+
+type LogLevel struct {
+	levels *logger.Levels
+	log    *zap.Logger
+}
+
+func New(levels *logger.Levels, log *zap.Logger) *LogLevel {
+	return &LogLevel{levels: levels, log: log}
+}
+
+func (l *LogLevel) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("", l.Get)
+	rg.PUT("", l.Set)
+}
+
+// Get godoc
+// @Summary Get log levels
+// @Description Returns the root log level and the per package overrides
+// @Tags Admin Log
+// @Produce json
+// @Success 200 {object} LevelResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/log/level [get]
+func (l *LogLevel) Get(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, l.response())
+}
+
+// Set godoc
+// @Summary Change a log level
+// @Description Changes the root level, or the level of a package when package is set. An empty level on a package removes its override. The change is not persisted across restarts.
+// @Tags Admin Log
+// @Accept json
+// @Produce json
+// @Param request body LevelRequest true "Level"
+// @Success 200 {object} LevelResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/log/level [put]
+func (l *LogLevel) Set(ctx *gin.Context) {
+	var req LevelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.JSONError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.Package == "" && req.Level == "" {
+		response.JSONError(ctx, http.StatusBadRequest, errMissingLevel)
+		return
+	}
+	if err := l.levels.SetLevel(req.Package, req.Level); err != nil {
+		response.JSONError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	logger.FromContext(ctx, l.log).Info("Log level changed",
+		zap.String("package", req.Package),
+		zap.String("level", req.Level),
+	)
+	ctx.JSON(http.StatusOK, l.response())
+}
+
+func (l *LogLevel) response() LevelResponse {
+	return LevelResponse{
+		Level:    l.levels.Root().String(),
+		Packages: l.levels.Packages(),
+	}
+}
+
+type LevelRequest struct {
+	Level   string `json:"level" example:"debug"`
+	Package string `json:"package" example:"sql"`
+}
+
+type LevelResponse struct {
+	Level    string            `json:"level" example:"info"`
+	Packages map[string]string `json:"packages"`
+}
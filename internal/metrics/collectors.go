@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"go-graphql/internal/storage/cache"
+	storage "go-graphql/internal/storage/sql"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector exposes storage.PoolStats, read on every scrape
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	maxConns          *prometheus.Desc
+	totalConns        *prometheus.Desc
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	acquires          *prometheus.Desc
+	acquireDuration   *prometheus.Desc
+	emptyAcquires     *prometheus.Desc
+	canceledAcquires  *prometheus.Desc
+	newConns          *prometheus.Desc
+	lifetimeDestroyed *prometheus.Desc
+	idleDestroyed     *prometheus.Desc
+}
+
+func newPoolCollector(name string, pool *pgxpool.Pool) *poolCollector {
+	labels := prometheus.Labels{"pool": name}
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", metric), help, nil, labels)
+	}
+	return &poolCollector{
+		pool:              pool,
+		maxConns:          desc("max_conns", "Maximum size of the pool."),
+		totalConns:        desc("total_conns", "Connections currently open."),
+		acquiredConns:     desc("acquired_conns", "Connections currently in use."),
+		idleConns:         desc("idle_conns", "Connections currently idle."),
+		acquires:          desc("acquires_total", "Connections acquired from the pool."),
+		acquireDuration:   desc("acquire_duration_seconds_total", "Time spent acquiring connections."),
+		emptyAcquires:     desc("empty_acquires_total", "Acquires that waited because the pool was empty."),
+		canceledAcquires:  desc("canceled_acquires_total", "Acquires canceled by their context."),
+		newConns:          desc("new_conns_total", "Connections opened."),
+		lifetimeDestroyed: desc("max_lifetime_destroyed_total", "Connections closed because of ConnMaxLifetime."),
+		idleDestroyed:     desc("max_idle_destroyed_total", "Connections closed because of ConnMaxIdleTime."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := storage.NewPoolStats(c.pool)
+	gauge := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value)
+	}
+	counter := func(desc *prometheus.Desc, value float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value)
+	}
+	gauge(c.maxConns, float64(s.MaxConns))
+	gauge(c.totalConns, float64(s.TotalConns))
+	gauge(c.acquiredConns, float64(s.AcquiredConns))
+	gauge(c.idleConns, float64(s.IdleConns))
+	counter(c.acquires, float64(s.AcquireCount))
+	counter(c.acquireDuration, float64(s.AcquireDurationMs)/1000)
+	counter(c.emptyAcquires, float64(s.EmptyAcquireCount))
+	counter(c.canceledAcquires, float64(s.CanceledAcquireCount))
+	counter(c.newConns, float64(s.NewConnsCount))
+	counter(c.lifetimeDestroyed, float64(s.MaxLifetimeDestroyCount))
+	counter(c.idleDestroyed, float64(s.MaxIdleDestroyCount))
+}
+
+// cacheCollector exposes the hit/miss counters of cache.Store
+type cacheCollector struct {
+	store   *cache.Store
+	hits    *prometheus.Desc
+	misses  *prometheus.Desc
+	evicted *prometheus.Desc
+}
+
+func newCacheCollector(store *cache.Store) *cacheCollector {
+	return &cacheCollector{
+		store:   store,
+		hits:    prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "hits_total"), "Cache reads served from Redis.", nil, nil),
+		misses:  prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "misses_total"), "Cache reads that found no usable entry.", nil, nil),
+		evicted: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "evictions_total"), "Stale or corrupt entries deleted on read.", nil, nil),
+	}
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(c, ch)
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	hits, misses, evicted := c.store.Counters()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(misses))
+	ch <- prometheus.MustNewConstMetric(c.evicted, prometheus.CounterValue, float64(evicted))
+}
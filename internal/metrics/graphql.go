@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// maxOperations bounds the operation names used as label values, names
+// beyond it are recorded as "other" since clients choose them freely
+const maxOperations = 100
+
+// GraphQL is a gqlgen extension recording operation and resolver metrics
+type GraphQL struct {
+	m          *Metrics
+	operations *operationNames
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = GraphQL{}
+
+// GraphQL returns the extension to Use on the gqlgen handler. The known
+// operation names, usually those of the trusted documents, are always
+// labelled by name, other names until maxOperations were seen
+func (m *Metrics) GraphQL(known ...string) GraphQL {
+	operations := &operationNames{known: make(map[string]bool, len(known)), seen: map[string]bool{}}
+	for _, name := range known {
+		operations.known[name] = true
+	}
+	return GraphQL{m: m, operations: operations}
+}
+
+func (GraphQL) ExtensionName() string {
+	return "Metrics"
+}
+
+func (GraphQL) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (g GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+	oc := graphql.GetOperationContext(ctx)
+	opType, opName := "unknown", oc.OperationName
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+		if opName == "" {
+			opName = oc.Operation.Name
+		}
+	}
+	if opName == "" {
+		opName = "anonymous"
+	} else {
+		opName = g.operations.label(opName)
+	}
+	result := "success"
+	if resp == nil || len(resp.Errors) > 0 {
+		result = "error"
+	}
+	g.m.graphqlOperations.WithLabelValues(opType, opName, result).Inc()
+	g.m.graphqlDuration.WithLabelValues(opType, opName).Observe(time.Since(oc.Stats.OperationStart).Seconds())
+	return resp
+}
+
+// InterceptField only times real resolvers, plain struct fields would
+// flood the histogram without telling anything
+func (g GraphQL) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	start := time.Now()
+	res, err := next(ctx)
+	object, field := fc.Object, fieldName(fc.Field.Field)
+	g.m.resolverDuration.WithLabelValues(object, field).Observe(time.Since(start).Seconds())
+	if err != nil {
+		g.m.resolverErrors.WithLabelValues(object, field).Inc()
+	}
+	return res, err
+}
+
+func fieldName(field *ast.Field) string {
+	if field == nil {
+		return "unknown"
+	}
+	return field.Name
+}
+
+// operationNames keeps the operation label set bounded
+type operationNames struct {
+	known map[string]bool
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (o *operationNames) label(name string) string {
+	if o.known[name] {
+		return name
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.seen[name] {
+		if len(o.seen) >= maxOperations {
+			return "other"
+		}
+		o.seen[name] = true
+	}
+	return name
+}
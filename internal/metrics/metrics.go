@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-graphql/internal/storage/cache"
+	storage "go-graphql/internal/storage/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "go_graphql"
+
+// Metrics owns the Prometheus registry of the app. A registry per instance
+// instead of the global one lets several apps run in the same process, as
+// the integration tests do.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	graphqlOperations *prometheus.CounterVec
+	graphqlDuration   *prometheus.HistogramVec
+	resolverDuration  *prometheus.HistogramVec
+	resolverErrors    *prometheus.CounterVec
+}
+
+func New(router *storage.Router, store *cache.Store) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served, by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being served.",
+		}),
+		graphqlOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "graphql_operations_total",
+			Help:      "GraphQL operations executed, by type, name and result.",
+		}, []string{"type", "operation", "result"}),
+		graphqlDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_operation_duration_seconds",
+			Help:      "GraphQL operation latency, from parsing to the response.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type", "operation"}),
+		resolverDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_resolver_duration_seconds",
+			Help:      "GraphQL resolver latency, by object and field.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"object", "field"}),
+		resolverErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "graphql_resolver_errors_total",
+			Help:      "GraphQL resolvers that returned an error, by object and field.",
+		}, []string{"object", "field"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.httpInFlight,
+		m.graphqlOperations, m.graphqlDuration, m.resolverDuration, m.resolverErrors,
+		newCacheCollector(store),
+	)
+	for name, pool := range router.Pools() {
+		m.registry.MustRegister(newPoolCollector(name, pool))
+	}
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry lets other packages register their own collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// HTTPStarted tracks a request in flight, call HTTPDone once it is served
+func (m *Metrics) HTTPStarted() {
+	m.httpInFlight.Inc()
+}
+
+// HTTPDone records a served request. route must be the route template, not
+// the raw path, to keep the number of series bounded.
+func (m *Metrics) HTTPDone(method, route string, status int, elapsed time.Duration) {
+	m.httpInFlight.Dec()
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
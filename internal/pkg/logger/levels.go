@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"go-graphql/internal/config"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels holds the root log level and the levels of named loggers, e.g.
+// log.Named("sql"), both can be changed while the app runs
+type Levels struct {
+	root     zap.AtomicLevel
+	mu       sync.RWMutex
+	packages map[string]zap.AtomicLevel
+}
+
+func NewLevels(cfg *config.Config) (*Levels, error) {
+	l := &Levels{root: zap.NewAtomicLevel(), packages: map[string]zap.AtomicLevel{}}
+	if err := l.SetLevel("", cfg.Log.Level); err != nil {
+		return nil, err
+	}
+	packages, err := cfg.Log.PackageLevels()
+	if err != nil {
+		return nil, err
+	}
+	for name, level := range packages {
+		if err := l.SetLevel(name, level); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// SetLevel changes the level of the named logger, or the root level when
+// name is empty. An empty level on a named logger makes it follow the root
+// level again.
+func (l *Levels) SetLevel(name, level string) error {
+	if name != "" && level == "" {
+		l.mu.Lock()
+		delete(l.packages, name)
+		l.mu.Unlock()
+		return nil
+	}
+	parsed := zapcore.InfoLevel
+	if level != "" {
+		var err error
+		if parsed, err = zapcore.ParseLevel(level); err != nil {
+			return err
+		}
+	}
+	if name == "" {
+		l.root.SetLevel(parsed)
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if atomic, ok := l.packages[name]; ok {
+		atomic.SetLevel(parsed)
+		return nil
+	}
+	l.packages[name] = zap.NewAtomicLevelAt(parsed)
+	return nil
+}
+
+// Root returns the level of loggers without a package override
+func (l *Levels) Root() zapcore.Level {
+	return l.root.Level()
+}
+
+// Packages returns the level of every package override
+func (l *Levels) Packages() map[string]string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	packages := make(map[string]string, len(l.packages))
+	for name, level := range l.packages {
+		packages[name] = level.Level().String()
+	}
+	return packages
+}
+
+// levelFor returns the level of the logger name, the most specific package
+// wins: "sql.router" follows "sql" unless "sql.router" is set itself
+func (l *Levels) levelFor(name string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for name != "" {
+		if level, ok := l.packages[name]; ok {
+			return level.Level()
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.root.Level()
+}
+
+// lowest returns the most verbose level in use, entries below it are
+// dropped before reaching levelFor
+func (l *Levels) lowest() zapcore.Level {
+	lowest := l.root.Level()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, level := range l.packages {
+		if level.Level() < lowest {
+			lowest = level.Level()
+		}
+	}
+	return lowest
+}
+
+// levelCore filters entries by the level of the logger that wrote them
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= c.levels.lowest()
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < c.levels.levelFor(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -2,22 +2,60 @@ package logger
 
 import (
 	"context"
+	"os"
 	"strings"
+	"time"
+
+	"go-graphql/internal/config"
 
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-func NewLogger() (*zap.Logger, error) {
-	cfg := zap.NewProductionConfig()
-	cfg.OutputPaths = []string{"stdout"}
-	return cfg.Build()
+// NewLogger builds the application logger from LogCfg, levels are read
+// from Levels on every entry so they can be changed at runtime
+func NewLogger(cfg *config.Config, levels *Levels) (*zap.Logger, error) {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	var encoder zapcore.Encoder
+	if cfg.Log.Format == "console" {
+		encoderCfg = zap.NewDevelopmentEncoderConfig()
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	}
+
+	sinks := []zapcore.WriteSyncer{zapcore.Lock(os.Stdout)}
+	if cfg.Log.File != "" {
+		sinks = append(sinks, zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.Log.File,
+			MaxSize:    cfg.Log.FileMaxSize,
+			MaxBackups: cfg.Log.FileMaxBackups,
+			MaxAge:     cfg.Log.FileMaxAge,
+			Compress:   cfg.Log.FileCompress,
+		}))
+	}
+
+	// the levels are checked by levelCore, the inner core accepts everything
+	var core zapcore.Core = zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(sinks...), zapcore.DebugLevel)
+	if cfg.Log.SamplingInitial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, cfg.Log.SamplingInitial, cfg.Log.SamplingThereafter)
+	}
+	return zap.New(&levelCore{Core: core, levels: levels},
+		zap.AddCaller(),
+		zap.AddStacktrace(zapcore.ErrorLevel),
+	), nil
 }
 
+// RegisterLoggerLifecycle makes log the global logger, used by Ctx outside
+// of a request, and flushes it on stop
 func RegisterLoggerLifecycle(lc fx.Lifecycle, log *zap.Logger) {
+	restore := zap.ReplaceGlobals(log)
 	lc.Append(fx.Hook{
 		OnStop: func(ctx context.Context) error {
 			log.Info("⏹️ On Stop Logger Life cycle ⏹️")
+			restore()
 			if err := log.Sync(); err != nil && !isIgnorableSyncError(err) {
 				return err
 			}
@@ -1,49 +1,49 @@
 package utils
 
-import "database/sql"
+import "github.com/jackc/pgx/v5/pgtype"
 
 // =============================================================================
-// UTILITY FUNCTIONS: GraphQL Pointer → SQLC Nullable
+// UTILITY FUNCTIONS: GraphQL Pointer → pgtype Nullable
 // =============================================================================
 
-// ToNullInt32 converts *int to sql.NullInt32
-func ToNullInt32(val *int) sql.NullInt32 {
+// ToInt4 converts *int to pgtype.Int4
+func ToInt4(val *int) pgtype.Int4 {
 	if val == nil {
-		return sql.NullInt32{Valid: false}
+		return pgtype.Int4{Valid: false}
 	}
-	return sql.NullInt32{Int32: int32(*val), Valid: true}
+	return pgtype.Int4{Int32: int32(*val), Valid: true}
 }
 
-// ToNullInt64 converts *int64 to sql.NullInt64
-func ToNullInt64(val *int64) sql.NullInt64 {
+// ToInt8 converts *int64 to pgtype.Int8
+func ToInt8(val *int64) pgtype.Int8 {
 	if val == nil {
-		return sql.NullInt64{Valid: false}
+		return pgtype.Int8{Valid: false}
 	}
-	return sql.NullInt64{Int64: *val, Valid: true}
+	return pgtype.Int8{Int64: *val, Valid: true}
 }
 
-// ToNullString converts *string to sql.NullString
-func ToNullString(val *string) sql.NullString {
+// ToText converts *string to pgtype.Text
+func ToText(val *string) pgtype.Text {
 	if val == nil {
-		return sql.NullString{Valid: false}
+		return pgtype.Text{Valid: false}
 	}
-	return sql.NullString{String: *val, Valid: true}
+	return pgtype.Text{String: *val, Valid: true}
 }
 
-// ToNullBool converts *bool to sql.NullBool
-func ToNullBool(val *bool) sql.NullBool {
+// ToBool converts *bool to pgtype.Bool
+func ToBool(val *bool) pgtype.Bool {
 	if val == nil {
-		return sql.NullBool{Valid: false}
+		return pgtype.Bool{Valid: false}
 	}
-	return sql.NullBool{Bool: *val, Valid: true}
+	return pgtype.Bool{Bool: *val, Valid: true}
 }
 
 // =============================================================================
-// UTILITY FUNCTIONS: SQLC Nullable → GraphQL Pointer
+// UTILITY FUNCTIONS: pgtype Nullable → GraphQL Pointer
 // =============================================================================
 
-// NullInt32ToPointer converts sql.NullInt32 to *int
-func NullInt32ToPointer(val sql.NullInt32) *int {
+// Int4ToPointer converts pgtype.Int4 to *int
+func Int4ToPointer(val pgtype.Int4) *int {
 	if !val.Valid {
 		return nil
 	}
@@ -51,24 +51,24 @@ func NullInt32ToPointer(val sql.NullInt32) *int {
 	return &result
 }
 
-// NullInt64ToPointer converts sql.NullInt64 to *int64
-func NullInt64ToPointer(val sql.NullInt64) *int64 {
+// Int8ToPointer converts pgtype.Int8 to *int64
+func Int8ToPointer(val pgtype.Int8) *int64 {
 	if !val.Valid {
 		return nil
 	}
 	return &val.Int64
 }
 
-// NullStringToPointer converts sql.NullString to *string
-func NullStringToPointer(val sql.NullString) *string {
+// TextToPointer converts pgtype.Text to *string
+func TextToPointer(val pgtype.Text) *string {
 	if !val.Valid || val.String == "" {
 		return nil
 	}
 	return &val.String
 }
 
-// NullBoolToPointer converts sql.NullBool to *bool
-func NullBoolToPointer(val sql.NullBool) *bool {
+// BoolToPointer converts pgtype.Bool to *bool
+func BoolToPointer(val pgtype.Bool) *bool {
 	if !val.Valid {
 		return nil
 	}
@@ -36,10 +36,15 @@ func (c *AdminProduct) RegisterRoutes(rg *gin.RouterGroup, cfg *config.Config) {
 // @Accept json
 // @Produce json
 // @Param product body dto.AdminCreateProductRequest true "Product to create"
+// @Param Idempotency-Key header string false "Replays the first response to retries sending the same key"
 // @Success 201 {object} dto.ProductResponse
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/admin/products [post]
 func (c *AdminProduct) CreateProduct(ctx *gin.Context) {
 	var req dto.AdminCreateProductRequest
@@ -63,10 +68,15 @@ func (c *AdminProduct) CreateProduct(ctx *gin.Context) {
 // @Produce json
 // @Param id path int true "Product ID"
 // @Param product body dto.AdminUpdateProductRequest true "Updated product details"
+// @Param Idempotency-Key header string false "Replays the first response to retries sending the same key"
 // @Success 200 {object} dto.ProductResponse
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/admin/products/{id} [put]
 func (c *AdminProduct) UpdateProduct(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
@@ -93,10 +103,15 @@ func (c *AdminProduct) UpdateProduct(ctx *gin.Context) {
 // @Description Delete a product by its ID
 // @Tags Admin Products
 // @Param id path int true "Product ID"
+// @Param Idempotency-Key header string false "Replays the first response to retries sending the same key"
 // @Success 204 "No Content"
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/admin/products/{id} [delete]
 func (c *AdminProduct) DeleteProduct(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
@@ -118,8 +133,11 @@ func (c *AdminProduct) DeleteProduct(ctx *gin.Context) {
 // @Param id path int true "Product ID"
 // @Success 200 {object} dto.ProductResponse
 // @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/admin/products/{id} [get]
 func (c *AdminProduct) GetProductByID(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
@@ -137,19 +155,33 @@ func (c *AdminProduct) GetProductByID(ctx *gin.Context) {
 }
 
 // ListProducts godoc
-// @Summary List all products
-// @Description Get a list of all products
+// @Summary List products
+// @Description Get a page of products, filtered and sorted. Pages are addressed by limit and offset, or by the cursor of the previous page
 // @Tags Admin Products
 // @Produce json
-// @Success 200 {array} dto.ClientListProductsResponse
+// @Param id query int false "Product ID"
+// @Param name query string false "Name contains, case insensitive"
+// @Param description query string false "Description contains, case insensitive"
+// @Param minPrice query int false "Minimum price"
+// @Param maxPrice query int false "Maximum price"
+// @Param isActive query bool false "Active products only, or inactive ones only"
+// @Param limit query int false "Page size, capped by the GraphQL max limit"
+// @Param offset query int false "Items to skip, not allowed with cursor"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param sort query string false "id, name, price or createdAt, prefixed by - for descending order" default(-createdAt)
+// @Success 200 {object} dto.ProductPage
+// @Header 200 {string} Link "first, prev, next and last pages"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Router /api/v1/admin/products [get]
 func (c *AdminProduct) ListProducts(ctx *gin.Context) {
-	products, err := c.Service.ListProductsWithoutFilter(ctx)
-	if err != nil {
-		response.JSONError(ctx, http.StatusInternalServerError, err)
+	page, ok := listProducts(ctx, c.Service)
+	if !ok {
 		return
 	}
-	ctx.JSON(http.StatusOK, products)
+	ctx.JSON(http.StatusOK, page)
 }
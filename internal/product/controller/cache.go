@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-graphql/internal/config"
+	"go-graphql/internal/http/response"
+	"go-graphql/internal/product/dto"
+	"go-graphql/internal/product/service"
+	"go-graphql/internal/storage/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errMissingKey = errors.New("missing key query parameter")
+
+type AdminCache struct {
+	Service *service.Product
+	Store   *cache.Store
+	cfg     *config.Config
+}
+
+func NewAdminCache(s *service.Product, store *cache.Store, cfg *config.Config) *AdminCache {
+	return &AdminCache{Service: s, Store: store, cfg: cfg}
+}
+
+func (c *AdminCache) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/keys", c.InspectKey)
+	rg.GET("/stats", c.Stats)
+	rg.POST("/warmup", c.WarmUp)
+	rg.DELETE("/products/:id", c.PurgeProduct)
+	rg.DELETE("/", c.PurgeAll)
+}
+
+// InspectKey godoc
+// @Summary Inspect a cached key
+// @Description Returns TTL, size, codec and decoded value of a key under the cache prefix
+// @Tags Admin Cache
+// @Produce json
+// @Param key query string true "Cache key, relative to the prefix (e.g. products:all)"
+// @Success 200 {object} cache.Entry
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/cache/keys [get]
+func (c *AdminCache) InspectKey(ctx *gin.Context) {
+	key := ctx.Query("key")
+	if key == "" {
+		response.JSONError(ctx, http.StatusBadRequest, errMissingKey)
+		return
+	}
+	entry, found, err := c.Store.Inspect(ctx, key)
+	if err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if !found {
+		response.JSONError(ctx, http.StatusNotFound, response.ErrNotFound)
+		return
+	}
+	ctx.JSON(http.StatusOK, entry)
+}
+
+// Stats godoc
+// @Summary Cache statistics
+// @Description Returns the number of keys under the cache prefix and the hit/miss counters of this instance
+// @Tags Admin Cache
+// @Produce json
+// @Success 200 {object} cache.Stats
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/cache/stats [get]
+func (c *AdminCache) Stats(ctx *gin.Context) {
+	stats, err := c.Store.Stats(ctx)
+	if err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, stats)
+}
+
+// WarmUp godoc
+// @Summary Warm up the product cache
+// @Description Preloads the product list and the latest products into the cache
+// @Tags Admin Cache
+// @Produce json
+// @Success 200 {object} dto.CacheWarmupResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/cache/warmup [post]
+func (c *AdminCache) WarmUp(ctx *gin.Context) {
+	count, err := c.Service.WarmUp(ctx, c.cfg.Redis.WarmupTopN)
+	if err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.CacheWarmupResponse{Products: count})
+}
+
+// PurgeProduct godoc
+// @Summary Purge a cached product
+// @Description Removes a product and the product list from the cache
+// @Tags Admin Cache
+// @Param id path int true "Product ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/cache/products/{id} [delete]
+func (c *AdminCache) PurgeProduct(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response.JSONError(ctx, http.StatusBadRequest, response.ErrInvalidID)
+		return
+	}
+	if err := c.Store.Delete(ctx, c.Store.KeyProduct(int32(id))); err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if err := c.Store.Delete(ctx, c.Store.KeyAllProducts()); err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// PurgeAll godoc
+// @Summary Purge the whole cache
+// @Description Removes every key under the configured cache prefix
+// @Tags Admin Cache
+// @Produce json
+// @Success 200 {object} dto.CachePurgeResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api/v1/admin/cache [delete]
+func (c *AdminCache) PurgeAll(ctx *gin.Context) {
+	deleted, err := c.Store.DeleteAll(ctx)
+	if err != nil {
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.CachePurgeResponse{Deleted: deleted})
+}
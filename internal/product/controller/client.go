@@ -1,6 +1,10 @@
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
 	"go-graphql/internal/http/response"
 	"go-graphql/internal/product/dto"
 	"go-graphql/internal/product/service"
@@ -28,9 +32,12 @@ func (c *ClientProduct) RegisterRoutes(rg *gin.RouterGroup) {
 // @Description Get a product by its ID
 // @Tags Products
 // @Param id path int true "Product ID"
+// @Param If-None-Match header string false "ETag of the cached product"
 // @Success 200 {object} dto.ProductResponse
+// @Success 304 "Not modified"
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
+// @Security ApiKeyAuth
 // @Router /api/v1/products/{id} [get]
 func (c *ClientProduct) GetProductByID(ctx *gin.Context) {
 	var product dto.ProductResponse
@@ -44,22 +51,60 @@ func (c *ClientProduct) GetProductByID(ctx *gin.Context) {
 		response.JSONError(ctx, http.StatusInternalServerError, err)
 		return
 	}
+	if response.NotModified(ctx, productETag(product)) {
+		return
+	}
 	ctx.JSON(http.StatusOK, product)
 }
 
 // ListProducts godoc
-// @Summary List all products
-// @Description Get a list of all products
+// @Summary List products
+// @Description Get a page of products, filtered and sorted. Pages are addressed by limit and offset, or by the cursor of the previous page
 // @Tags Products
 // @Produce json
-// @Success 200 {array} dto.ClientListProductsResponse
+// @Param id query int false "Product ID"
+// @Param name query string false "Name contains, case insensitive"
+// @Param description query string false "Description contains, case insensitive"
+// @Param minPrice query int false "Minimum price"
+// @Param maxPrice query int false "Maximum price"
+// @Param isActive query bool false "Active products only, or inactive ones only"
+// @Param limit query int false "Page size, capped by the GraphQL max limit"
+// @Param offset query int false "Items to skip, not allowed with cursor"
+// @Param cursor query string false "nextCursor of the previous page"
+// @Param sort query string false "id, name, price or createdAt, prefixed by - for descending order" default(-createdAt)
+// @Param If-None-Match header string false "ETag of the cached page"
+// @Success 200 {object} dto.ProductPage
+// @Header 200 {string} Link "first, prev, next and last pages"
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.ErrorResponse
 // @Failure 500 {object} response.ErrorResponse
+// @Security ApiKeyAuth
 // @Router /api/v1/products [get]
 func (c *ClientProduct) ListProducts(ctx *gin.Context) {
-	products, err := c.Service.ListProductsWithoutFilter(ctx)
-	if err != nil {
-		response.JSONError(ctx, http.StatusInternalServerError, err)
+	page, ok := listProducts(ctx, c.Service)
+	if !ok {
+		return
+	}
+	if response.NotModified(ctx, productsETag(page)) {
 		return
 	}
-	ctx.JSON(http.StatusOK, products)
+	ctx.JSON(http.StatusOK, page)
+}
+
+// productETag changes with every update of the product
+func productETag(p dto.ProductResponse) string {
+	return fmt.Sprintf(`"product-%d-v%d"`, p.ID, p.Version)
+}
+
+// productsETag changes when any product of the page is created, updated
+// or deleted, the order changes or the total changes
+func productsETag(page dto.ProductPage) string {
+	h := sha256.New()
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(page.Total)))
+	h.Write([]byte(page.NextCursor))
+	for _, p := range page.Items {
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(p.ID)))
+		h.Write(binary.BigEndian.AppendUint32(nil, uint32(p.Version)))
+	}
+	return `"products-` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
 }
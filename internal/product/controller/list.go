@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go-graphql/internal/http/response"
+	"go-graphql/internal/product/dto"
+	"go-graphql/internal/product/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listProducts binds the list query parameters and returns the requested
+// page with its Link header, it answers the errors itself and reports
+// false then
+func listProducts(ctx *gin.Context, s *service.Product) (dto.ProductPage, bool) {
+	var q dto.ProductListQuery
+	if err := ctx.ShouldBindQuery(&q); err != nil {
+		response.JSONError(ctx, http.StatusBadRequest, err)
+		return dto.ProductPage{}, false
+	}
+	page, err := s.List(ctx, q)
+	switch {
+	case errors.Is(err, service.ErrInvalidPagination),
+		errors.Is(err, service.ErrInvalidSort),
+		errors.Is(err, service.ErrInvalidCursor):
+		response.JSONError(ctx, http.StatusBadRequest, err)
+		return dto.ProductPage{}, false
+	case err != nil:
+		response.JSONError(ctx, http.StatusInternalServerError, err)
+		return dto.ProductPage{}, false
+	}
+	if links := pageLinks(ctx, page, q.Cursor != ""); links != "" {
+		ctx.Header("Link", links)
+	}
+	return page, true
+}
+
+// pageLinks builds the RFC 8288 links of the page from the request URL,
+// cursor pages only link forward
+func pageLinks(ctx *gin.Context, page dto.ProductPage, cursorMode bool) string {
+	link := func(rel string, set func(q url.Values)) string {
+		u := *ctx.Request.URL
+		q := u.Query()
+		q.Del("offset")
+		q.Del("cursor")
+		set(q)
+		u.RawQuery = q.Encode()
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+	}
+	offset := func(n int) func(q url.Values) {
+		return func(q url.Values) {
+			if n > 0 {
+				q.Set("offset", strconv.Itoa(n))
+			}
+		}
+	}
+
+	links := []string{link("first", offset(0))}
+	switch {
+	case cursorMode || page.Limit == 0:
+		if page.NextCursor != "" {
+			links = append(links, link("next", func(q url.Values) {
+				q.Set("cursor", page.NextCursor)
+			}))
+		}
+	default:
+		if page.Offset > 0 {
+			links = append(links, link("prev", offset(max(page.Offset-page.Limit, 0))))
+		}
+		if next := page.Offset + len(page.Items); int64(next) < page.Total {
+			links = append(links, link("next", offset(next)))
+		}
+		if page.Total > 0 {
+			links = append(links, link("last", offset(int((page.Total-1)/int64(page.Limit))*page.Limit)))
+		}
+	}
+	return strings.Join(links, ", ")
+}
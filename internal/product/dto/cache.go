@@ -0,0 +1,9 @@
+package dto
+
+type CachePurgeResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+type CacheWarmupResponse struct {
+	Products int `json:"products"`
+}
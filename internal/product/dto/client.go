@@ -1,11 +1,15 @@
 package dto
 
+import "time"
+
 type ProductResponse struct {
-	ID          int32  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Price       int64  `json:"price"`
-	IsActive    bool   `json:"isActive,omitempty"`
+	ID          int32     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Price       int64     `json:"price"`
+	IsActive    bool      `json:"isActive"`
+	Version     int32     `json:"version"`
+	CreatedAt   time.Time `json:"createdAt"`
 }
 
 type ClientListProductsResponse []ProductResponse
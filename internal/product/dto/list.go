@@ -0,0 +1,35 @@
+package dto
+
+// ProductListQuery filters, sorts and pages the product lists, REST binds
+// it from the query string and GraphQL builds it from its arguments
+type ProductListQuery struct {
+	ID          *int    `form:"id"`
+	Name        *string `form:"name"`
+	Description *string `form:"description"`
+	MinPrice    *int64  `form:"minPrice"`
+	MaxPrice    *int64  `form:"maxPrice"`
+	IsActive    *bool   `form:"isActive"`
+	Limit       *int    `form:"limit"`
+	Offset      *int    `form:"offset"`
+	// Cursor is the NextCursor of the previous page, it excludes Offset
+	Cursor string `form:"cursor"`
+	// Sort is one of id, name, price and createdAt, prefixed with - for
+	// descending order
+	Sort string `form:"sort"`
+}
+
+// Filtered reports whether any filter is set
+func (q ProductListQuery) Filtered() bool {
+	return q.ID != nil || q.Name != nil || q.Description != nil ||
+		q.MinPrice != nil || q.MaxPrice != nil || q.IsActive != nil
+}
+
+// ProductPage is one page of a product list
+type ProductPage struct {
+	Items  []ProductResponse `json:"items"`
+	Total  int64             `json:"total"` // products matching the filters
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+	// NextCursor fetches the next page, empty on the last one
+	NextCursor string `json:"nextCursor,omitempty"`
+}
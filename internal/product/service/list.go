@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-graphql/internal/graph/model"
+	"go-graphql/internal/pkg/logger"
+	"go-graphql/internal/pkg/logger/utils"
+	"go-graphql/internal/product/dto"
+	"go-graphql/internal/storage/sql/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Sort orders of the product lists, GraphQL keeps listing by id
+const (
+	DefaultSort = "-createdAt"
+	graphqlSort = "id"
+)
+
+// sortKeys maps the sort fields clients send to the keys
+// ListProductsWithFilters orders by
+var sortKeys = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"price":     "price",
+	"createdAt": "created_at",
+}
+
+// cursor is the position after the last product of a page, in the order
+// it was issued for
+type cursor struct {
+	Sort      string     `json:"s"`
+	ID        int32      `json:"i"`
+	Name      string     `json:"n,omitempty"`
+	Price     int64      `json:"p,omitempty"`
+	CreatedAt *time.Time `json:"c,omitempty"`
+}
+
+// List returns a page of the products matching q, newest first unless
+// q.Sort says otherwise
+func (s *Product) List(ctx context.Context, q dto.ProductListQuery) (dto.ProductPage, error) {
+	if q.Sort == "" {
+		q.Sort = DefaultSort
+	}
+	return s.list(ctx, q)
+}
+
+// ListProducts serves the GraphQL products query through the same path as
+// the REST lists
+func (s *Product) ListProducts(ctx context.Context, filter *model.ProductFilter, pagination *model.PaginationInput) (*model.ProductConnection, error) {
+	q := dto.ProductListQuery{Sort: graphqlSort}
+	if filter != nil {
+		q.ID, q.Name, q.Description = filter.ID, filter.Name, filter.Description
+		q.MinPrice, q.MaxPrice, q.IsActive = filter.MinPrice, filter.MaxPrice, filter.IsActive
+	}
+	if pagination != nil {
+		q.Limit, q.Offset = pagination.Limit, pagination.Offset
+	}
+	page, err := s.list(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*model.Product
+	for _, p := range page.Items {
+		result = append(result, &model.Product{
+			ID:          int(p.ID),
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			IsActive:    p.IsActive,
+		})
+	}
+	return &model.ProductConnection{
+		Products: result,
+		Total:    int(page.Total),
+	}, nil
+}
+
+// list is the one filtering path of the product lists. The unfiltered
+// newest first list is paged from the cached catalog, the others are
+// queried with one extra row telling whether a next page exists
+func (s *Product) list(ctx context.Context, q dto.ProductListQuery) (dto.ProductPage, error) {
+	params, err := s.listParams(q)
+	if err != nil {
+		return dto.ProductPage{}, err
+	}
+	limit, offset := int(params.Limit.Int64), int(params.Offset.Int64)
+	page := dto.ProductPage{Limit: limit, Offset: offset}
+
+	if !q.Filtered() && q.Cursor == "" && q.Sort == DefaultSort {
+		all, err := s.allProducts(ctx)
+		if err != nil {
+			logger.FromContext(ctx, s.log).Error("Failed to list products", zap.Error(err))
+			return dto.ProductPage{}, err
+		}
+		start := min(offset, len(all))
+		end := min(start+limit, len(all))
+		page.Items, page.Total = all[start:end], int64(len(all))
+		if end < len(all) && end > start {
+			page.NextCursor = encodeCursor(q.Sort, page.Items[len(page.Items)-1])
+		}
+		return page, nil
+	}
+
+	params.Limit.Int64++
+	products, err := s.query.ListProductsWithFilters(ctx, params)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Error("Failed to list products", zap.Error(err))
+		return dto.ProductPage{}, err
+	}
+	total, err := s.query.CountProductsWithFilters(ctx, countParams(q))
+	if err != nil {
+		logger.FromContext(ctx, s.log).Error("Failed to count products", zap.Error(err))
+		return dto.ProductPage{}, err
+	}
+
+	more := len(products) > limit
+	if more {
+		products = products[:limit]
+	}
+	page.Items, page.Total = toProductResponses(products), total
+	if more && len(page.Items) > 0 {
+		page.NextCursor = encodeCursor(q.Sort, page.Items[len(page.Items)-1])
+	}
+	return page, nil
+}
+
+// listParams validates q and converts it to SQLC params, the page limit
+// is capped by GraphQL.MaxLimit
+func (s *Product) listParams(q dto.ProductListQuery) (sqlc.ListProductsWithFiltersParams, error) {
+	pagination := &model.PaginationInput{Limit: q.Limit, Offset: q.Offset}
+	limit, offset := pagination.PageLimit(), pagination.PageOffset()
+	switch maxLimit := s.cfg.GraphQL.MaxLimit; {
+	case limit < 0:
+		return sqlc.ListProductsWithFiltersParams{}, fmt.Errorf("%w: limit %d must not be negative", ErrInvalidPagination, limit)
+	case maxLimit > 0 && limit > maxLimit:
+		return sqlc.ListProductsWithFiltersParams{}, fmt.Errorf("%w: limit %d exceeds the maximum of %d", ErrInvalidPagination, limit, maxLimit)
+	case offset < 0:
+		return sqlc.ListProductsWithFiltersParams{}, fmt.Errorf("%w: offset %d must not be negative", ErrInvalidPagination, offset)
+	case offset > 0 && q.Cursor != "":
+		return sqlc.ListProductsWithFiltersParams{}, fmt.Errorf("%w: offset cannot be combined with a cursor", ErrInvalidPagination)
+	}
+
+	key, ok := sortKeys[strings.TrimPrefix(q.Sort, "-")]
+	if !ok {
+		return sqlc.ListProductsWithFiltersParams{}, fmt.Errorf("%w: %q, expected id, name, price or createdAt, prefixed with - for descending order",
+			ErrInvalidSort, q.Sort)
+	}
+	if strings.HasPrefix(q.Sort, "-") {
+		key = "-" + key
+	}
+
+	params := sqlc.ListProductsWithFiltersParams{
+		ID:                 utils.ToInt4(q.ID),
+		ProductName:        utils.ToText(q.Name),
+		ProductDescription: utils.ToText(q.Description),
+		MinPrice:           utils.ToInt8(q.MinPrice),
+		MaxPrice:           utils.ToInt8(q.MaxPrice),
+		IsActive:           utils.ToBool(q.IsActive),
+		Sort:               key,
+		Limit:              pgtype.Int8{Int64: int64(limit), Valid: true},
+		Offset:             pgtype.Int8{Int64: int64(offset), Valid: true},
+	}
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor, q.Sort)
+		if err != nil {
+			return sqlc.ListProductsWithFiltersParams{}, err
+		}
+		params.AfterID = pgtype.Int4{Int32: c.ID, Valid: true}
+		params.AfterName = pgtype.Text{String: c.Name, Valid: true}
+		params.AfterPrice = pgtype.Int8{Int64: c.Price, Valid: true}
+		if c.CreatedAt != nil {
+			params.AfterCreatedAt = pgtype.Timestamp{Time: *c.CreatedAt, Valid: true}
+		}
+	}
+	return params, nil
+}
+
+func countParams(q dto.ProductListQuery) sqlc.CountProductsWithFiltersParams {
+	return sqlc.CountProductsWithFiltersParams{
+		ID:                 utils.ToInt4(q.ID),
+		ProductName:        utils.ToText(q.Name),
+		ProductDescription: utils.ToText(q.Description),
+		MinPrice:           utils.ToInt8(q.MinPrice),
+		MaxPrice:           utils.ToInt8(q.MaxPrice),
+		IsActive:           utils.ToBool(q.IsActive),
+	}
+}
+
+// encodeCursor keeps only the sort value of the last product, the cursor
+// is opaque to clients
+func encodeCursor(sort string, last dto.ProductResponse) string {
+	c := cursor{Sort: sort, ID: last.ID}
+	switch strings.TrimPrefix(sort, "-") {
+	case "name":
+		c.Name = last.Name
+	case "price":
+		c.Price = last.Price
+	case "createdAt":
+		c.CreatedAt = &last.CreatedAt
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value, sort string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return cursor{}, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+	if c.Sort != sort {
+		return cursor{}, fmt.Errorf("%w: issued for sort %q, not %q", ErrInvalidCursor, c.Sort, sort)
+	}
+	if strings.TrimPrefix(sort, "-") == "createdAt" && c.CreatedAt == nil {
+		return cursor{}, fmt.Errorf("%w: missing position", ErrInvalidCursor)
+	}
+	return c, nil
+}
@@ -2,31 +2,43 @@ package service
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"go-graphql/internal/config"
-	"go-graphql/internal/graph/model"
-	"go-graphql/internal/pkg/logger/utils"
+	"go-graphql/internal/pkg/logger"
 	"go-graphql/internal/product/dto"
+	"go-graphql/internal/storage"
 	"go-graphql/internal/storage/cache"
 	"go-graphql/internal/storage/sql/sqlc"
 
 	"go.uber.org/zap"
 )
 
+var (
+	// ErrInvalidPagination is returned when a page limit or offset is
+	// negative, the limit exceeds GraphQL.MaxLimit or an offset comes
+	// with a cursor
+	ErrInvalidPagination = errors.New("invalid pagination")
+	ErrInvalidSort       = errors.New("invalid sort")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+)
+
 type Product struct {
 	query  *sqlc.Queries
+	tx     *storage.TxManager
 	log    *zap.Logger
 	memory *cache.Store
 	cfg    *config.Config
 }
 
 func New(q *sqlc.Queries,
+	tx *storage.TxManager,
 	log *zap.Logger,
 	memory *cache.Store,
 	cfg *config.Config) *Product {
 	return &Product{
 		query:  q,
-		log:    log,
+		tx:     tx,
+		log:    log.Named("product"),
 		memory: memory,
 		cfg:    cfg,
 	}
@@ -39,19 +51,24 @@ func (s *Product) Create(ctx context.Context, req dto.AdminCreateProductRequest)
 		Price:              req.Price,
 		IsActive:           true,
 	}
-	product, err := s.query.CreateProduct(ctx, arg)
+	var product sqlc.Product
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, q *sqlc.Queries) error {
+		var err error
+		product, err = q.CreateProduct(ctx, arg)
+		if err != nil {
+			return err
+		}
+		storage.AfterCommit(ctx, func(ctx context.Context) {
+			s.memory.Set(ctx, s.memory.KeyProduct(product.ID), product, s.cfg.Redis.DefaultTTL)
+			s.memory.Delete(ctx, s.memory.KeyAllProducts())
+		})
+		return nil
+	})
 	if err != nil {
 		return dto.ProductResponse{}, err
 	}
-	s.log.Info("Product created", zap.Int32("id", product.ID))
-	s.memory.Set(ctx, s.memory.KeyProduct(product.ID), product, s.cfg.Redis.DefaultTTL)
-	s.memory.Delete(ctx, s.memory.KeyAllProducts())
-	return dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.ProductName,
-		Description: product.ProductDescription,
-		Price:       product.Price,
-	}, nil
+	logger.FromContext(ctx, s.log).Info("Product created", zap.Int32("id", product.ID))
+	return toProductResponse(product), nil
 }
 
 func (s *Product) Update(ctx context.Context, req dto.AdminUpdateProductRequest) (dto.ProductResponse, error) {
@@ -62,24 +79,36 @@ func (s *Product) Update(ctx context.Context, req dto.AdminUpdateProductRequest)
 		Price:              req.Price,
 		IsActive:           req.IsActive,
 	}
-	product, err := s.query.UpdateProduct(ctx, arg)
+	var product sqlc.Product
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, q *sqlc.Queries) error {
+		var err error
+		product, err = q.UpdateProduct(ctx, arg)
+		if err != nil {
+			return err
+		}
+		storage.AfterCommit(ctx, func(ctx context.Context) {
+			s.memory.Set(ctx, s.memory.KeyProduct(product.ID), product, s.cfg.Redis.DefaultTTL)
+			s.memory.Delete(ctx, s.memory.KeyAllProducts())
+		})
+		return nil
+	})
 	if err != nil {
 		return dto.ProductResponse{}, err
 	}
-	s.memory.Set(ctx, s.memory.KeyProduct(product.ID), product, s.cfg.Redis.DefaultTTL)
-	s.memory.Delete(ctx, s.memory.KeyAllProducts())
-	return dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.ProductName,
-		Description: product.ProductDescription,
-		Price:       product.Price,
-	}, nil
+	return toProductResponse(product), nil
 }
 
 func (s *Product) Delete(ctx context.Context, id int32) error {
-	s.memory.Delete(ctx, s.memory.KeyProduct(id))
-	s.memory.Delete(ctx, s.memory.KeyAllProducts())
-	return s.query.DeleteProduct(ctx, id)
+	return s.tx.WithinTx(ctx, func(ctx context.Context, q *sqlc.Queries) error {
+		if err := q.DeleteProduct(ctx, id); err != nil {
+			return err
+		}
+		storage.AfterCommit(ctx, func(ctx context.Context) {
+			s.memory.Delete(ctx, s.memory.KeyProduct(id))
+			s.memory.Delete(ctx, s.memory.KeyAllProducts())
+		})
+		return nil
+	})
 }
 
 func (s *Product) GetProductByID(ctx context.Context, id int32) (dto.ProductResponse, error) {
@@ -92,16 +121,11 @@ func (s *Product) GetProductByID(ctx context.Context, id int32) (dto.ProductResp
 		}
 		s.memory.Set(ctx, s.memory.KeyProduct(product.ID), product, s.cfg.Redis.DefaultTTL)
 	}
-	result := dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.ProductName,
-		Description: product.ProductDescription,
-		Price:       product.Price,
-	}
-	return result, nil
+	return toProductResponse(product), nil
 }
 
-func (s *Product) ListProductsWithoutFilter(ctx context.Context) (dto.ClientListProductsResponse, error) {
+// allProducts returns every product, newest first, from the cache
+func (s *Product) allProducts(ctx context.Context) ([]dto.ProductResponse, error) {
 	var resp []dto.ProductResponse
 	if err := s.memory.Get(ctx, s.memory.KeyAllProducts(), &resp); err == nil {
 		return resp, nil
@@ -110,98 +134,48 @@ func (s *Product) ListProductsWithoutFilter(ctx context.Context) (dto.ClientList
 	if err != nil {
 		return nil, err
 	}
-	resp = make([]dto.ProductResponse, 0, len(products))
-	for _, product := range products {
-		resp = append(resp, dto.ProductResponse{
-			ID:          product.ID,
-			Name:        product.ProductName,
-			Description: product.ProductDescription,
-			Price:       product.Price,
-		})
-	}
+	resp = toProductResponses(products)
 	s.memory.Set(ctx, s.memory.KeyAllProducts(), resp, s.cfg.Redis.DefaultTTL)
 	return resp, nil
 }
 
-func (s *Product) ListProducts(ctx context.Context, filter *model.ProductFilter, pagination *model.PaginationInput) (*model.ProductConnection, error) {
-	params := s.graphqlFilterToSQLCParams(filter, pagination)
-	products, err := s.query.ListProductsWithFilters(ctx, params)
-	if err != nil {
-		s.log.Error("Failed to list products", zap.Error(err))
-		return nil, err
-	}
-
-	paramsCount := s.graphqlCountProductsFilterToSQLCParams(filter)
-	total, err := s.query.CountProductsWithFilters(ctx, paramsCount)
+// WarmUp preloads the product list and the topN latest products into the
+// cache, it returns the number of products cached individually
+func (s *Product) WarmUp(ctx context.Context, topN int) (int, error) {
+	products, err := s.query.ListProducts(ctx)
 	if err != nil {
-		s.log.Error("Failed to count products", zap.Error(err))
-		return nil, err
+		return 0, err
 	}
-
-	var result []*model.Product
-	for _, p := range products {
-		result = append(result, &model.Product{
-			ID:          int(p.ID),
-			Name:        p.ProductName,
-			Description: p.ProductDescription,
-			Price:       p.Price,
-			IsActive:    p.IsActive,
-		})
+	if err := s.memory.Set(ctx, s.memory.KeyAllProducts(), toProductResponses(products), s.cfg.Redis.DefaultTTL); err != nil {
+		return 0, err
 	}
-	return &model.ProductConnection{
-		Products: result,
-		Total:    int(total),
-	}, nil
-}
-
-// graphqlFilterToSQLCParams converts GraphQL ProductFilter to SQLC params
-func (s *Product) graphqlFilterToSQLCParams(
-	filter *model.ProductFilter,
-	pagination *model.PaginationInput,
-) sqlc.ListProductsWithFiltersParams {
-	// Initialize with defaults
-	params := sqlc.ListProductsWithFiltersParams{
-		Limit:  sql.NullInt64{Int64: 10, Valid: true},
-		Offset: sql.NullInt64{Int64: 0, Valid: true},
+	if topN > len(products) {
+		topN = len(products)
 	}
-
-	// Apply filter if provided
-	if filter != nil {
-		params.ID = utils.ToNullInt32(filter.ID)
-		params.ProductName = utils.ToNullString(filter.Name)
-		params.ProductDescription = utils.ToNullString(filter.Description)
-		params.MinPrice = utils.ToNullInt64(filter.MinPrice)
-		params.MaxPrice = utils.ToNullInt64(filter.MaxPrice)
-		params.IsActive = utils.ToNullBool(filter.IsActive)
-	}
-
-	if pagination != nil {
-		if pagination.Limit != nil {
-			params.Limit = sql.NullInt64{Int64: int64(*pagination.Limit), Valid: true}
-		}
-		if pagination.Offset != nil {
-			params.Offset = sql.NullInt64{Int64: int64(*pagination.Offset), Valid: true}
+	for _, product := range products[:topN] {
+		if err := s.memory.Set(ctx, s.memory.KeyProduct(product.ID), product, s.cfg.Redis.DefaultTTL); err != nil {
+			return 0, err
 		}
 	}
-
-	return params
+	return topN, nil
 }
 
-func (s *Product) graphqlCountProductsFilterToSQLCParams(
-	filter *model.ProductFilter,
-) sqlc.CountProductsWithFiltersParams {
-	// Initialize with defaults
-	params := sqlc.CountProductsWithFiltersParams{}
-
-	// Apply filter if provided
-	if filter != nil {
-		params.ID = utils.ToNullInt32(filter.ID)
-		params.ProductName = utils.ToNullString(filter.Name)
-		params.ProductDescription = utils.ToNullString(filter.Description)
-		params.MinPrice = utils.ToNullInt64(filter.MinPrice)
-		params.MaxPrice = utils.ToNullInt64(filter.MaxPrice)
-		params.IsActive = utils.ToNullBool(filter.IsActive)
+func toProductResponses(products []sqlc.Product) []dto.ProductResponse {
+	resp := make([]dto.ProductResponse, 0, len(products))
+	for _, product := range products {
+		resp = append(resp, toProductResponse(product))
 	}
+	return resp
+}
 
-	return params
+func toProductResponse(product sqlc.Product) dto.ProductResponse {
+	return dto.ProductResponse{
+		ID:          product.ID,
+		Name:        product.ProductName,
+		Description: product.ProductDescription,
+		Price:       product.Price,
+		IsActive:    product.IsActive,
+		Version:     product.Version,
+		CreatedAt:   product.CreatedAt,
+	}
 }
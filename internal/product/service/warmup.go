@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"go-graphql/internal/config"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const warmupTimeout = 30 * time.Second
+
+// RegisterWarmup preloads the product cache in the background once the app
+// has started, so a deploy or a Redis flush does not send the first wave of
+// traffic to Postgres
+func RegisterWarmup(lc fx.Lifecycle, s *Product, cfg *config.Config, log *zap.Logger) {
+	if !cfg.Redis.WarmupEnabled {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ctx, cancelTimeout := context.WithTimeout(ctx, warmupTimeout)
+				defer cancelTimeout()
+
+				start := time.Now()
+				count, err := s.WarmUp(ctx, cfg.Redis.WarmupTopN)
+				if err != nil {
+					log.Warn("Cache warm-up failed", zap.Error(err))
+					return
+				}
+				log.Info("Cache warmed up",
+					zap.Int("products", count),
+					zap.Duration("took", time.Since(start)),
+				)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type subjectKey struct{}
+
+// WithSubject records the authenticated caller, such as an API key id or
+// a JWT subject, authentication middlewares call it so the caller is
+// charged the same bucket whatever its IP. Unverified credentials must not
+// be recorded, rotating them would hand out fresh buckets
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// Subject returns the subject recorded by WithSubject
+func Subject(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectKey{}).(string)
+	return subject
+}
+
+// ClientKey returns the bucket key of the caller, its subject when
+// authenticated, the client IP otherwise
+func ClientKey(ctx context.Context, clientIP string) string {
+	if subject := Subject(ctx); subject != "" {
+		return "sub:" + subject
+	}
+	return "ip:" + clientIP
+}
+
+type client struct {
+	key    string
+	result atomic.Pointer[Result]
+}
+
+type clientCtxKey struct{}
+
+// WithClient records the bucket key the GraphQL extension charges, the
+// returned func reports the last result charged so the HTTP layer can set
+// the status and headers once the operation was rejected or admitted
+func WithClient(ctx context.Context, key string) (context.Context, func() (Result, bool)) {
+	c := &client{key: key}
+	last := func() (Result, bool) {
+		if res := c.result.Load(); res != nil {
+			return *res, true
+		}
+		return Result{}, false
+	}
+	return context.WithValue(ctx, clientCtxKey{}, c), last
+}
+
+func clientFrom(ctx context.Context) *client {
+	c, _ := ctx.Value(clientCtxKey{}).(*client)
+	return c
+}
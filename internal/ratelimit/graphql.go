@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// GraphQL error codes set in the error extensions
+const (
+	CodeRateLimited     = "RATE_LIMITED"
+	CodeCostExceedsRate = "RATE_LIMIT_COST_EXCEEDED"
+	CodeUnavailable     = "RATE_LIMIT_UNAVAILABLE"
+)
+
+// GraphQL is a gqlgen extension charging every operation its query cost,
+// the complexity gqlgen computes from the schema, to the bucket of the
+// client recorded by WithClient
+type GraphQL struct {
+	limiter *Limiter
+	es      graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &GraphQL{}
+
+// GraphQL returns the extension to Use on the gqlgen handler
+func (l *Limiter) GraphQL() *GraphQL {
+	return &GraphQL{limiter: l}
+}
+
+func (*GraphQL) ExtensionName() string {
+	return "RateLimit"
+}
+
+func (g *GraphQL) Validate(es graphql.ExecutableSchema) error {
+	g.es = es
+	return nil
+}
+
+func (g *GraphQL) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	c := clientFrom(ctx)
+	p := g.limiter.Policy(GroupGraphQL)
+	if c == nil || !p.Enabled() || opCtx.Operation == nil {
+		return nil
+	}
+
+	cost := max(1, complexity.Calculate(ctx, g.es, opCtx.Operation, opCtx.Variables))
+	res, err := g.limiter.Allow(ctx, p, c.key, cost)
+	switch {
+	case errors.Is(err, ErrCostExceedsBurst):
+		gqlErr := gqlerror.Errorf("operation has cost %d, which exceeds the rate limit of %d", cost, p.Burst)
+		errcode.Set(gqlErr, CodeCostExceedsRate)
+		return gqlErr
+	case err != nil:
+		gqlErr := gqlerror.Errorf("rate limit unavailable, retry later")
+		errcode.Set(gqlErr, CodeUnavailable)
+		return gqlErr
+	}
+
+	c.result.Store(&res)
+	if !res.Allowed {
+		gqlErr := gqlerror.Errorf("rate limit exceeded, operation cost %d, %d remaining", cost, res.Remaining)
+		errcode.Set(gqlErr, CodeRateLimited)
+		gqlErr.Extensions["retryAfter"] = Seconds(res.RetryAfter)
+		return gqlErr
+	}
+	return nil
+}
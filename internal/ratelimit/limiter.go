@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"go-graphql/internal/config"
+	"go-graphql/internal/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Route groups, part of the bucket keys
+const (
+	GroupClient  = "client"
+	GroupAdmin   = "admin"
+	GroupGraphQL = "graphql"
+)
+
+// ErrCostExceedsBurst is returned when a single request costs more than a
+// full bucket, waiting would never let it through
+var ErrCostExceedsBurst = errors.New("request cost exceeds the rate limit burst")
+
+// Policy is a token bucket holding Burst tokens refilled at Rate per minute
+type Policy struct {
+	Group string
+	Rate  int
+	Burst int
+}
+
+// Enabled reports whether the policy limits anything
+func (p Policy) Enabled() bool {
+	return p.Rate > 0
+}
+
+// Window is how long an empty bucket takes to refill
+func (p Policy) Window() time.Duration {
+	return time.Duration(p.Burst) * time.Minute / time.Duration(p.Rate)
+}
+
+// Result is the state of a bucket after charging a request
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // until enough tokens are back, zero when allowed
+	Reset      time.Duration // until the bucket is full again
+}
+
+// tokenBucket refills the bucket for the time elapsed since the last call
+// and takes cost tokens when enough are left, the clock is Redis' so
+// instances with skewed clocks share the same buckets
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+else
+	retry = math.ceil((cost - tokens) / rate)
+end
+local reset = math.ceil((burst - tokens) / rate)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// Limiter keeps token buckets in Redis so every instance charges the same
+// bucket for a client
+type Limiter struct {
+	client   redis.UniversalClient
+	prefix   string
+	enabled  bool
+	failOpen bool
+	policies map[string]Policy
+	log      *zap.Logger
+}
+
+func New(cfg *config.Config, client redis.UniversalClient, log *zap.Logger) *Limiter {
+	rl := cfg.RateLimit
+	return &Limiter{
+		client:   client,
+		prefix:   cfg.Redis.Prefix + ":ratelimit",
+		enabled:  rl.Enabled,
+		failOpen: rl.FailOpen,
+		policies: map[string]Policy{
+			GroupClient:  newPolicy(GroupClient, rl.ClientRate, rl.ClientBurst),
+			GroupAdmin:   newPolicy(GroupAdmin, rl.AdminRate, rl.AdminBurst),
+			GroupGraphQL: newPolicy(GroupGraphQL, rl.GraphQLRate, rl.GraphQLBurst),
+		},
+		log: log.Named("ratelimit"),
+	}
+}
+
+func newPolicy(group string, rate, burst int) Policy {
+	if burst <= 0 {
+		burst = rate
+	}
+	return Policy{Group: group, Rate: rate, Burst: burst}
+}
+
+// Policy returns the policy of a route group, disabled when rate limiting
+// is off or the group has no rate
+func (l *Limiter) Policy(group string) Policy {
+	if !l.enabled {
+		return Policy{Group: group}
+	}
+	return l.policies[group]
+}
+
+// Allow charges cost tokens to the bucket of key under the policy. When
+// Redis fails the request is let through if the limiter fails open,
+// Allowed is false and the error returned otherwise
+func (l *Limiter) Allow(ctx context.Context, p Policy, key string, cost int) (Result, error) {
+	if cost > p.Burst {
+		return Result{Limit: p.Burst}, fmt.Errorf("%w: cost %d, burst %d", ErrCostExceedsBurst, cost, p.Burst)
+	}
+
+	perMs := float64(p.Rate) / float64(time.Minute/time.Millisecond)
+	vals, err := tokenBucket.Run(ctx, l.client, []string{l.key(p, key)},
+		strconv.FormatFloat(perMs, 'g', -1, 64), p.Burst, cost).Int64Slice()
+	if err != nil {
+		logger.FromContext(ctx, l.log).Warn("Rate limit check failed",
+			zap.String("group", p.Group), zap.Bool("failOpen", l.failOpen), zap.Error(err))
+		// the bucket state is unknown, Limit stays zero so no headers are sent
+		if l.failOpen {
+			return Result{Allowed: true}, nil
+		}
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    vals[0] == 1,
+		Limit:      p.Burst,
+		Remaining:  int(vals[1]),
+		RetryAfter: time.Duration(vals[2]) * time.Millisecond,
+		Reset:      time.Duration(vals[3]) * time.Millisecond,
+	}, nil
+}
+
+func (l *Limiter) key(p Policy, key string) string {
+	return l.prefix + ":" + p.Group + ":" + key
+}
+
+// Seconds rounds d up to whole seconds for the Retry-After and
+// RateLimit-Reset headers
+func Seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"go-graphql/internal/config"
+	"go-graphql/internal/graph/generated"
+	"go-graphql/internal/graph/limits"
+	"go-graphql/internal/graph/persisted"
+	"go-graphql/internal/graph/resolvers"
+	"go-graphql/internal/metrics"
+	"go-graphql/internal/ratelimit"
+	"go-graphql/internal/tracing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewGraphQLHandler builds the gqlgen server with the configured
+// transports, introspection is only served in development. Persisted
+// queries live in Redis so every instance knows the hashes registered
+// through the others
+func NewGraphQLHandler(
+	cfg *config.Config,
+	resolver *resolvers.Resolver,
+	m *metrics.Metrics,
+	tp trace.TracerProvider,
+	limiter *ratelimit.Limiter,
+	apq *persisted.QueryCache,
+	manifest persisted.Manifest,
+) *handler.Server {
+	schema := generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Complexity: limits.Complexity(cfg.GraphQL.MaxLimit),
+	})
+	srv := handler.New(schema)
+	if cfg.GraphQLTransport(config.GraphQLTransportWebSocket) {
+		srv.AddTransport(transport.Websocket{
+			KeepAlivePingInterval: seconds(cfg.GraphQL.WebSocketKeepAlive),
+		})
+	}
+	if cfg.GraphQLTransport(config.GraphQLTransportGET) {
+		srv.AddTransport(transport.GET{})
+	}
+	if cfg.GraphQLTransport(config.GraphQLTransportPOST) {
+		srv.AddTransport(transport.POST{})
+	}
+	if cfg.GraphQLTransport(config.GraphQLTransportMultipart) {
+		srv.AddTransport(transport.MultipartForm{})
+	}
+	if cfg.GraphQL.QueryCacheSize > 0 {
+		srv.SetQueryCache(lru.New[*ast.QueryDocument](cfg.GraphQL.QueryCacheSize))
+	}
+
+	if cfg.IsDevelopment() {
+		srv.Use(extension.Introspection{})
+	}
+	// trusted documents resolve their hashes before APQ looks them up, only
+	// trusted documents are accepted when clients may not register queries
+	if manifest != nil || cfg.GraphQL.TrustedOnly {
+		srv.Use(persisted.NewTrusted(manifest, cfg.GraphQL.TrustedOnly))
+	}
+	if cfg.GraphQL.APQEnabled && !cfg.GraphQL.TrustedOnly {
+		srv.Use(extension.AutomaticPersistedQuery{Cache: apq})
+	}
+
+	srv.Use(m.GraphQL(manifest.OperationNames()...))
+	srv.Use(tracing.NewGraphQL(tp))
+	// oversized operations are rejected before they are charged to the rate limit
+	if cfg.GraphQL.MaxDepth > 0 {
+		srv.Use(limits.NewDepthLimit(cfg.GraphQL.MaxDepth))
+	}
+	if cfg.GraphQL.MaxComplexity > 0 {
+		srv.Use(extension.FixedComplexityLimit(cfg.GraphQL.MaxComplexity))
+	}
+	srv.Use(limiter.GraphQL())
+	return srv
+}
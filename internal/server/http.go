@@ -8,13 +8,17 @@ import (
 
 	"go-graphql/internal/config"
 	"go-graphql/internal/graph/resolvers"
+	"go-graphql/internal/health"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/metrics"
+	"go-graphql/internal/tracing"
 
 	// gqlgen generated package
 	product "go-graphql/internal/product/service"
 
-	"github.com/gin-contrib/timeout"
-
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -23,6 +27,11 @@ type HTTPServer struct {
 	server *http.Server
 	config *config.Config
 	log    *zap.Logger
+	health *health.Health
+	// closing is canceled when the server stops, hijacked connections
+	// such as WebSockets are not closed by http.Server.Shutdown
+	closing context.Context
+	certs   *certReloader // nil without TLS
 }
 
 // NewGraphQLResolver wires your services into the gqlgen resolvers.
@@ -32,49 +41,140 @@ func NewGraphQLResolver(productSvc *product.Product) *resolvers.Resolver {
 	}
 }
 
-func NewGinEngine() *gin.Engine {
+func NewGinEngine(log *zap.Logger, m *metrics.Metrics, cfg *config.Config, tp trace.TracerProvider) (*gin.Engine, error) {
 	if gin.Mode() != gin.ReleaseMode {
 		gin.SetMode(gin.DebugMode)
 	} else {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	log = log.Named("http")
 	r := gin.New()
-	r.Use(gin.Logger(),
-		gin.Recovery(),
-		timeout.New(timeout.WithTimeout(60*time.Second)))
+	// handlers pass *gin.Context as context.Context, let it reach the values
+	// middlewares attach to the request context
+	r.ContextWithFallback = true
+	// X-Forwarded-For is only honoured from the configured proxies, client
+	// IPs key the rate limits and must not be spoofable
+	if err := r.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	// tracing runs first so request logs carry the trace id
+	r.Use(middleware.Tracing(tracing.ServiceName(cfg), tp),
+		middleware.RequestID(log),
+		middleware.RequestLogger(log),
+		middleware.Metrics(m),
+		middleware.Recovery(log),
+		middleware.SecurityHeaders(cfg.HTTP.Security, "/playground", "/swagger/"),
+		middleware.CORS(cfg.HTTP.CORS),
+		middleware.Compress(cfg.HTTP.Compression),
+		middleware.ReadYourWrites())
 
-	return r
+	return r, nil
 }
 
-func NewHTTPServer(engine *gin.Engine, cfg *config.Config, logger *zap.Logger) *HTTPServer {
+func NewHTTPServer(engine *gin.Engine, cfg *config.Config, logger *zap.Logger, health *health.Health) (*HTTPServer, error) {
 	httpServer := http.Server{
-		Addr:    fmt.Sprintf("%s:%d", cfg.HTTPAddress, cfg.HTTPPort),
-		Handler: engine,
+		Addr:              fmt.Sprintf("%s:%d", cfg.HTTPAddress, cfg.HTTPPort),
+		Handler:           engine,
+		ReadTimeout:       seconds(cfg.HTTP.ReadTimeout),
+		ReadHeaderTimeout: seconds(cfg.HTTP.ReadHeaderTimeout),
+		WriteTimeout:      seconds(cfg.HTTP.WriteTimeout),
+		IdleTimeout:       seconds(cfg.HTTP.IdleTimeout),
 	}
-	return &HTTPServer{
+	hs := &HTTPServer{
 		server: &httpServer,
 		config: cfg,
 		log:    logger,
+		health: health,
+	}
+
+	if cfg.HTTP.TLS.Enabled {
+		certs, err := newCertReloader(cfg.HTTP.TLS, logger)
+		if err != nil {
+			return nil, err
+		}
+		hs.certs = certs
+		httpServer.TLSConfig = certs.tlsConfig()
+	} else if cfg.HTTP.H2C {
+		// HTTP/2 with prior knowledge, HTTP/1.1 keeps working for browsers
+		// and WebSocket upgrades
+		httpServer.Protocols = new(http.Protocols)
+		httpServer.Protocols.SetHTTP1(true)
+		httpServer.Protocols.SetUnencryptedHTTP2(true)
 	}
+
+	var closeAll context.CancelFunc
+	hs.closing, closeAll = context.WithCancel(context.Background())
+	httpServer.RegisterOnShutdown(closeAll)
+	return hs, nil
+}
+
+// CloseOnShutdown cancels the context of long-lived requests, such as
+// GraphQL WebSocket subscriptions, when the server shuts down
+func (hs *HTTPServer) CloseOnShutdown(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(hs.closing, cancel)
+		defer stop()
+		// gqlgen sends the reason to WebSocket clients before closing
+		ctx = transport.AppendCloseReason(ctx, "server shutting down")
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
 func StartHTTPServer(lc fx.Lifecycle, hs *HTTPServer) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			if hs.certs != nil && hs.config.HTTP.TLS.ReloadInterval > 0 {
+				go hs.certs.watch(hs.closing, seconds(hs.config.HTTP.TLS.ReloadInterval))
+			}
 			go func() {
 				hs.log.Info("Server running",
-					zap.String("url", fmt.Sprintf("http://%s:%d/playground", hs.config.HTTPAddress, hs.config.HTTPPort)),
+					zap.String("url", fmt.Sprintf("%s://%s:%d",
+						hs.config.HTTPScheme(), hs.config.HTTPAddress, hs.config.HTTPPort)),
+					zap.Bool("h2c", hs.config.HTTP.H2C),
 				)
-				if err := hs.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				var err error
+				if hs.certs != nil {
+					// the certificate comes from TLSConfig
+					err = hs.server.ListenAndServeTLS("", "")
+				} else {
+					err = hs.server.ListenAndServe()
+				}
+				if err != nil && err != http.ErrServerClosed {
 					hs.log.Error("Server error", zap.Error(err))
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			// readiness fails first, the drain period gives load balancers
+			// time to notice before the listeners close
+			hs.health.Drain()
+			if drain := seconds(hs.config.HTTP.DrainPeriod); drain > 0 {
+				hs.log.Info("Draining connections", zap.Duration("period", drain))
+				select {
+				case <-time.After(drain):
+				case <-ctx.Done():
+				}
+			}
+
 			hs.log.Info("Stopping server...")
-			return hs.server.Shutdown(ctx)
+			if timeout := seconds(hs.config.HTTP.ShutdownTimeout); timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+			if err := hs.server.Shutdown(ctx); err != nil {
+				hs.log.Warn("In-flight requests cut by the shutdown timeout", zap.Error(err))
+				return hs.server.Close()
+			}
+			return nil
 		},
 	})
 }
+
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
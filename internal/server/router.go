@@ -3,49 +3,123 @@ package server
 import (
 	"fmt"
 	"go-graphql/docs"
+	apikeyController "go-graphql/internal/apikey/controller"
+	apikey "go-graphql/internal/apikey/service"
 	"go-graphql/internal/config"
-	"go-graphql/internal/graph/generated"
+	"go-graphql/internal/graph/persisted"
 	"go-graphql/internal/graph/resolvers"
 	"go-graphql/internal/health"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/idempotency"
+	"go-graphql/internal/loglevel"
+	"go-graphql/internal/metrics"
 	"go-graphql/internal/product/controller"
+	"go-graphql/internal/ratelimit"
 	"log"
 	"net/http"
 
-	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func RegisterRoutes(
 	engine *gin.Engine,
+	hs *HTTPServer,
 	health *health.Health,
+	logLevel *loglevel.LogLevel,
+	m *metrics.Metrics,
+	limiter *ratelimit.Limiter,
+	tp trace.TracerProvider,
 	cfg *config.Config,
 	adminProduct *controller.AdminProduct,
 	clientProduct *controller.ClientProduct,
+	adminCache *controller.AdminCache,
+	adminAPIKey *apikeyController.AdminAPIKey,
+	apiKeys *apikey.APIKey,
+	idempotent *idempotency.Store,
 	resolver *resolvers.Resolver,
+	apq *persisted.QueryCache,
+	manifest persisted.Manifest,
 ) {
 	log.Println("🚀 Registering routes...")
 
-	// Health check
+	// Health check, the readiness checks have their own timeouts
 	engine.GET("/health", health.Handle)
+	engine.GET("/health/live", health.Live)
+	engine.GET("/health/ready", health.Ready)
 
-	// Admin Product routes
-	adminGroup := engine.Group("/api/v1/admin/products")
+	// Prometheus metrics
+	engine.GET("/metrics", gin.WrapH(m.Handler()))
+
+	// API keys are authenticated before the rate limit so each key is
+	// charged its own bucket, requests without one pass through
+	apiKeyAuth := middleware.APIKeyAuth(apiKeys)
+
+	// Admin routes need an API key or a verified client certificate, either
+	// one is enough. Anonymous admin calls are allowed only when no client CA
+	// is configured, keys need the admin scope outside products
+	admin := engine.Group("/api/v1/admin",
+		apiKeyAuth,
+		middleware.RequireAuthenticated(cfg.HTTP.AdminAnonymous && cfg.HTTP.TLS.ClientCAFile == ""),
+		middleware.RateLimit(limiter, ratelimit.GroupAdmin),
+		middleware.Timeout(seconds(cfg.HTTP.AdminTimeout)))
+	adminScope := middleware.RequireScope(apikey.ScopeAdmin)
+	admin.GET("/db/stats", adminScope, health.DBStats)
+
+	// Admin log level routes
+	logLevelGroup := admin.Group("/log/level", adminScope)
+	logLevel.RegisterRoutes(logLevelGroup)
+
+	// Admin Product routes, retried writes sending the same
+	// Idempotency-Key get the first response back
+	adminGroup := admin.Group("/products",
+		middleware.RequireScopes(apikey.ScopeProductsRead, apikey.ScopeProductsWrite),
+		middleware.Idempotency(idempotent))
 	adminProduct.RegisterRoutes(adminGroup, cfg)
 
+	// Admin Cache routes
+	cacheGroup := admin.Group("/cache", adminScope)
+	adminCache.RegisterRoutes(cacheGroup)
+
+	// Admin API key routes
+	apiKeyGroup := admin.Group("/api-keys", adminScope)
+	adminAPIKey.RegisterRoutes(apiKeyGroup)
+
 	// Client Product routes
-	clientGroup := engine.Group("/api/v1/products")
+	clientGroup := engine.Group("/api/v1/products",
+		apiKeyAuth,
+		middleware.RequireScope(apikey.ScopeProductsRead),
+		middleware.RateLimit(limiter, ratelimit.GroupClient),
+		middleware.Timeout(seconds(cfg.HTTP.RequestTimeout)),
+		middleware.CacheControl(map[string]string{
+			"/api/v1/products/":    cfg.HTTP.CacheControl.Products,
+			"/api/v1/products/:id": cfg.HTTP.CacheControl.Product,
+		}))
 	clientProduct.RegisterRoutes(clientGroup)
 
-	// GraphQL schema + handler
-	schema := generated.NewExecutableSchema(generated.Config{Resolvers: resolver})
-	graphqlHandler := handler.NewDefaultServer(schema)
+	// GraphQL handler
+	graphqlHandler := NewGraphQLHandler(cfg, resolver, m, tp, limiter, apq, manifest)
 
-	// GraphQL endpoints
-	engine.POST("/query", gin.WrapH(graphqlHandler))
-	engine.GET("/playground", gin.WrapH(playground.Handler("GraphQL Playground", "/query")))
+	// GraphQL endpoints, GET also upgrades WebSocket subscriptions which
+	// are closed when the server shuts down, operations are charged by cost
+	graphqlTimeout := middleware.Timeout(seconds(cfg.HTTP.GraphQLTimeout))
+	graphqlRateLimit := middleware.GraphQLRateLimit(limiter)
+	// the schema only has queries, reading it is all a key may do
+	graphqlScope := middleware.RequireScope(apikey.ScopeProductsRead)
+	if cfg.GraphQLTransport(config.GraphQLTransportPOST) || cfg.GraphQLTransport(config.GraphQLTransportMultipart) {
+		engine.POST("/query", apiKeyAuth, graphqlScope, graphqlRateLimit, graphqlTimeout, gin.WrapH(graphqlHandler))
+	}
+	if cfg.GraphQLTransport(config.GraphQLTransportGET) || cfg.GraphQLTransport(config.GraphQLTransportWebSocket) {
+		engine.GET("/query", apiKeyAuth, graphqlScope, graphqlRateLimit, graphqlTimeout,
+			gin.WrapH(hs.CloseOnShutdown(graphqlHandler)))
+	}
+	// the playground relies on introspection, both are development only
+	if cfg.IsDevelopment() {
+		engine.GET("/playground", gin.WrapH(playground.Handler("GraphQL Playground", "/query")))
+	}
 
 	// Swagger docs
 	docs.SwaggerInfo.Title = "My API"
@@ -53,7 +127,7 @@ func RegisterRoutes(
 	docs.SwaggerInfo.Description = "This is a sample API with Gin and Swagger."
 	docs.SwaggerInfo.Host = fmt.Sprintf("%s:%d", cfg.HTTPAddress, cfg.HTTPPort)
 	docs.SwaggerInfo.BasePath = "/"
-	docs.SwaggerInfo.Schemes = []string{"http"} // or {"https"} in production
+	docs.SwaggerInfo.Schemes = []string{cfg.HTTPScheme()}
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Handle 404 for unknown routes
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"go-graphql/internal/config"
+
+	"go.uber.org/zap"
+)
+
+// certReloader serves the certificate and client CAs read from disk and
+// polls the files, so rotated certificates are used without a restart
+type certReloader struct {
+	cfg     config.HTTPTLSCfg
+	log     *zap.Logger
+	current atomic.Pointer[tlsFiles]
+	modTime time.Time
+}
+
+type tlsFiles struct {
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool // nil when mutual TLS is disabled
+}
+
+func newCertReloader(cfg config.HTTPTLSCfg, log *zap.Logger) (*certReloader, error) {
+	r := &certReloader{cfg: cfg, log: log}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.modTime, _ = r.lastModified()
+	return r, nil
+}
+
+func (r *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load HTTP_TLS_CERT_FILE/HTTP_TLS_KEY_FILE: %w", err)
+	}
+	files := &tlsFiles{cert: &cert}
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read HTTP_TLS_CLIENT_CA_FILE: %w", err)
+		}
+		files.clientCAs = x509.NewCertPool()
+		if !files.clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("HTTP_TLS_CLIENT_CA_FILE %q contains no PEM certificate", r.cfg.ClientCAFile)
+		}
+	}
+	r.current.Store(files)
+	return nil
+}
+
+// lastModified returns the newest modification time of the TLS files
+func (r *certReloader) lastModified() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// watch reloads the files when they change, a failed reload keeps the
+// previous certificate so a half-written rotation does not break serving
+func (r *certReloader) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			modTime, err := r.lastModified()
+			if err != nil || !modTime.After(r.modTime) {
+				continue
+			}
+			if err := r.load(); err != nil {
+				r.log.Error("TLS certificate reload failed, keeping the previous one", zap.Error(err))
+				continue
+			}
+			r.modTime = modTime
+			r.log.Info("TLS certificate reloaded")
+		}
+	}
+}
+
+// tlsConfig reads the current files on every handshake. Client
+// certificates are requested but optional, RequireAuthenticated accepts
+// verified ones on admin routes.
+func (r *certReloader) tlsConfig() *tls.Config {
+	minVersion := uint16(tls.VersionTLS12)
+	if r.cfg.MinVersion == "1.3" {
+		minVersion = tls.VersionTLS13
+	}
+	return &tls.Config{
+		MinVersion: minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			files := r.current.Load()
+			cfg := &tls.Config{
+				MinVersion:   minVersion,
+				Certificates: []tls.Certificate{*files.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if files.clientCAs != nil {
+				cfg.ClientCAs = files.clientCAs
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+			return cfg, nil
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Entry describes a cached blob without knowing the Go type it was stored as
+type Entry struct {
+	Key        string      `json:"key"`
+	TTLSeconds int64       `json:"ttlSeconds"`
+	SizeBytes  int         `json:"sizeBytes"`
+	Codec      string      `json:"codec,omitempty"`
+	Compressed bool        `json:"compressed"`
+	Value      interface{} `json:"value,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Stats reports the counters collected by this instance since start and
+// the number of keys currently stored under the prefix
+type Stats struct {
+	Prefix   string  `json:"prefix"`
+	Keys     int64   `json:"keys"`
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	Evicted  uint64  `json:"evicted"`
+	HitRatio float64 `json:"hitRatio"`
+}
+
+type counters struct {
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	evicted atomic.Uint64
+}
+
+const scanBatch = 500
+
+// Key scopes a relative key under the store prefix, keys that already carry
+// the prefix are returned unchanged
+func (r *Store) Key(key string) string {
+	if strings.HasPrefix(key, r.prefix+":") {
+		return key
+	}
+	return r.prefix + ":" + key
+}
+
+// Inspect returns the metadata and decoded value of a cached key, found is
+// false when the key does not exist
+func (r *Store) Inspect(ctx context.Context, key string) (Entry, bool, error) {
+	key = r.Key(key)
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	entry := Entry{Key: key, TTLSeconds: int64(ttl / time.Second), SizeBytes: len(data)}
+	codec, compressed, value, err := describe(data)
+	if codec != nil {
+		entry.Codec = codec.Name()
+	}
+	entry.Compressed = compressed
+	entry.Value = value
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	return entry, true, nil
+}
+
+// DeleteAll removes every key under the store prefix and returns how many
+// keys were deleted
+func (r *Store) DeleteAll(ctx context.Context) (int64, error) {
+	var deleted atomic.Int64
+	err := r.forEachNode(ctx, func(ctx context.Context, node redis.UniversalClient) error {
+		return scanKeys(ctx, node, r.prefix+":*", func(keys []string) error {
+			// delete one by one so cluster nodes never see cross-slot commands
+			pipe := node.Pipeline()
+			for _, k := range keys {
+				pipe.Del(ctx, k)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return err
+			}
+			deleted.Add(int64(len(keys)))
+			return nil
+		})
+	})
+	return deleted.Load(), err
+}
+
+// Stats counts the keys under the store prefix and returns the hit/miss
+// counters of this instance
+func (r *Store) Stats(ctx context.Context) (Stats, error) {
+	var keys atomic.Int64
+	err := r.forEachNode(ctx, func(ctx context.Context, node redis.UniversalClient) error {
+		return scanKeys(ctx, node, r.prefix+":*", func(batch []string) error {
+			keys.Add(int64(len(batch)))
+			return nil
+		})
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats := Stats{
+		Prefix:  r.prefix,
+		Keys:    keys.Load(),
+		Hits:    r.counters.hits.Load(),
+		Misses:  r.counters.misses.Load(),
+		Evicted: r.counters.evicted.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats, nil
+}
+
+// Counters returns the hit, miss and eviction counters of this instance
+// without querying Redis
+func (r *Store) Counters() (hits, misses, evicted uint64) {
+	return r.counters.hits.Load(), r.counters.misses.Load(), r.counters.evicted.Load()
+}
+
+// forEachNode runs fn on every master of a cluster, or once on the client
+// for single node and Sentinel setups
+func (r *Store) forEachNode(ctx context.Context, fn func(context.Context, redis.UniversalClient) error) error {
+	if cluster, ok := r.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return fn(ctx, node)
+		})
+	}
+	return fn(ctx, r.client)
+}
+
+func scanKeys(ctx context.Context, client redis.UniversalClient, match string, fn func([]string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, match, scanBatch).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
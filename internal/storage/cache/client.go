@@ -1,14 +1,116 @@
 package cache
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"go-graphql/internal/config"
+	"os"
+	"strings"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func NewClient(cfg *config.Config) *redis.Client {
-	return redis.NewClient(&redis.Options{
-		Addr: cfg.Redis.DSN,
-		DB:   cfg.Redis.DB,
-	})
+// NewClient builds the redis client matching Redis.Mode: a single node, a
+// Sentinel-managed failover client or a cluster client, every command is
+// traced through tp
+func NewClient(cfg *config.Config, tp trace.TracerProvider) (redis.UniversalClient, error) {
+	opts, err := universalOptions(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+	var client redis.UniversalClient
+	switch cfg.Redis.Mode {
+	case config.RedisModeSentinel:
+		client = redis.NewFailoverClient(opts.Failover())
+	case config.RedisModeCluster:
+		client = redis.NewClusterClient(opts.Cluster())
+	default:
+		client = redis.NewClient(opts.Simple())
+	}
+	if err := redisotel.InstrumentTracing(client, redisotel.WithTracerProvider(tp)); err != nil {
+		return nil, fmt.Errorf("redis tracing: %w", err)
+	}
+	return client, nil
+}
+
+// universalOptions merges the addresses parsed from Redis.DSN with the
+// explicit credentials and TLS settings, explicit settings win over the
+// ones embedded in a redis:// or rediss:// URL
+func universalOptions(rc config.RedisCfg) (*redis.UniversalOptions, error) {
+	opts := &redis.UniversalOptions{
+		DB:               rc.DB,
+		MasterName:       rc.MasterName,
+		SentinelUsername: rc.SentinelUsername,
+		SentinelPassword: rc.SentinelPassword,
+	}
+
+	for _, addr := range strings.Split(rc.DSN, ",") {
+		addr = strings.TrimSpace(addr)
+		if !strings.Contains(addr, "://") {
+			opts.Addrs = append(opts.Addrs, addr)
+			continue
+		}
+		parsed, err := redis.ParseURL(addr)
+		if err != nil {
+			return nil, fmt.Errorf("parse REDIS_DSN %q: %w", addr, err)
+		}
+		opts.Addrs = append(opts.Addrs, parsed.Addr)
+		if opts.Username == "" {
+			opts.Username = parsed.Username
+		}
+		if opts.Password == "" {
+			opts.Password = parsed.Password
+		}
+		if parsed.DB != 0 {
+			opts.DB = parsed.DB
+		}
+		if parsed.TLSConfig != nil && opts.TLSConfig == nil {
+			opts.TLSConfig = parsed.TLSConfig
+		}
+	}
+
+	if rc.Username != "" {
+		opts.Username = rc.Username
+	}
+	if rc.Password != "" {
+		opts.Password = rc.Password
+	}
+	if rc.TLS.Enabled {
+		tlsCfg, err := tlsConfig(rc.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts.TLSConfig = tlsCfg
+	}
+	return opts, nil
+}
+
+func tlsConfig(tc config.RedisTLSCfg) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         tc.ServerName,
+		InsecureSkipVerify: tc.InsecureSkipVerify,
+	}
+	if tc.CAFile != "" {
+		pem, err := os.ReadFile(tc.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read REDIS_TLS_CA_FILE: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("REDIS_TLS_CA_FILE %q contains no certificates", tc.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if tc.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(tc.CertFile, tc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load redis client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
 }
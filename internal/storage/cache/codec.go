@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec serializes values stored in the cache
+type Codec interface {
+	// ID identifies the codec inside the envelope header, it must never change
+	ID() byte
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+const (
+	codecIDJSON    byte = 1
+	codecIDMsgpack byte = 2
+)
+
+type JSONCodec struct{}
+
+func (JSONCodec) ID() byte     { return codecIDJSON }
+func (JSONCodec) Name() string { return "json" }
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) ID() byte     { return codecIDMsgpack }
+func (MsgpackCodec) Name() string { return "msgpack" }
+
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+// codecs lists every codec a blob can be decoded with, regardless of the
+// codec configured for writing, so switching codecs does not need a flush
+var codecs = map[byte]Codec{
+	codecIDJSON:    JSONCodec{},
+	codecIDMsgpack: MsgpackCodec{},
+}
+
+// CodecByName returns the codec registered under name, an empty name
+// selects JSON
+func CodecByName(name string) (Codec, error) {
+	if name == "" {
+		return JSONCodec{}, nil
+	}
+	for _, c := range codecs {
+		if c.Name() == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown cache codec %q", name)
+}
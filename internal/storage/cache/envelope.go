@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Every cached blob starts with a fixed header:
+//
+//	[0]   magic byte
+//	[1]   envelope format version
+//	[2]   codec id
+//	[3]   flags
+//	[4:8] schema fingerprint (big endian)
+//
+// The schema fingerprint is derived from the configured cache version and
+// the shape of the Go type being stored, so a struct change after a deploy
+// turns old entries into misses instead of silently decoding stale shapes.
+const (
+	envelopeMagic      byte = 0xC7
+	envelopeVersion    byte = 1
+	envelopeHeaderSize      = 8
+
+	flagGzip byte = 1 << 0
+)
+
+var (
+	// ErrStaleEntry is returned when a cached blob was written with another
+	// schema or envelope version, the entry is evicted before returning
+	ErrStaleEntry = errors.New("cache: stale entry evicted")
+	// ErrCorruptEntry is returned when a cached blob cannot be decoded
+	ErrCorruptEntry = errors.New("cache: corrupt entry")
+)
+
+type envelope struct {
+	codec             Codec
+	version           int
+	compressThreshold int
+}
+
+func (e envelope) encode(value interface{}) ([]byte, error) {
+	payload, err := e.codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags byte
+	if e.compressThreshold > 0 && len(payload) >= e.compressThreshold {
+		if payload, err = gzipBytes(payload); err != nil {
+			return nil, err
+		}
+		flags |= flagGzip
+	}
+
+	buf := make([]byte, envelopeHeaderSize, envelopeHeaderSize+len(payload))
+	buf[0] = envelopeMagic
+	buf[1] = envelopeVersion
+	buf[2] = e.codec.ID()
+	buf[3] = flags
+	binary.BigEndian.PutUint32(buf[4:8], e.fingerprint(reflect.TypeOf(value)))
+	return append(buf, payload...), nil
+}
+
+func (e envelope) decode(data []byte, dest interface{}) error {
+	if len(data) >= envelopeHeaderSize &&
+		binary.BigEndian.Uint32(data[4:8]) != e.fingerprint(reflect.TypeOf(dest)) {
+		return ErrStaleEntry
+	}
+	codec, _, payload, err := unwrap(data)
+	if err != nil {
+		return err
+	}
+	if err := codec.Unmarshal(payload, dest); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptEntry, err)
+	}
+	return nil
+}
+
+// describe decodes a blob without a destination type, for inspection only
+func describe(data []byte) (codec Codec, compressed bool, value interface{}, err error) {
+	codec, compressed, payload, err := unwrap(data)
+	if err != nil {
+		return codec, compressed, nil, err
+	}
+	if err = codec.Unmarshal(payload, &value); err != nil {
+		return codec, compressed, nil, fmt.Errorf("%w: %v", ErrCorruptEntry, err)
+	}
+	return codec, compressed, value, nil
+}
+
+// unwrap checks the header and returns the codec and the uncompressed payload
+func unwrap(data []byte) (codec Codec, compressed bool, payload []byte, err error) {
+	if len(data) < envelopeHeaderSize || data[0] != envelopeMagic || data[1] != envelopeVersion {
+		return nil, false, nil, ErrStaleEntry
+	}
+	codec, ok := codecs[data[2]]
+	if !ok {
+		return nil, false, nil, fmt.Errorf("%w: unknown codec id %d", ErrCorruptEntry, data[2])
+	}
+	compressed = data[3]&flagGzip != 0
+
+	payload = data[envelopeHeaderSize:]
+	if compressed {
+		if payload, err = gunzipBytes(payload); err != nil {
+			return codec, compressed, nil, fmt.Errorf("%w: %v", ErrCorruptEntry, err)
+		}
+	}
+	return codec, compressed, payload, nil
+}
+
+func (e envelope) fingerprint(t reflect.Type) uint32 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d|%s", e.version, typeShape(t))
+	return h.Sum32()
+}
+
+var shapes sync.Map // reflect.Type -> string
+
+// typeShape describes the serialized shape of t, pointers are dereferenced
+// so a value and a pointer to it share the same shape
+func typeShape(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "nil"
+	}
+	if s, ok := shapes.Load(t); ok {
+		return s.(string)
+	}
+	var sb strings.Builder
+	writeShape(&sb, t, map[reflect.Type]bool{})
+	shapes.Store(t, sb.String())
+	return sb.String()
+}
+
+func writeShape(sb *strings.Builder, t reflect.Type, seen map[reflect.Type]bool) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		sb.WriteString("*")
+		writeShape(sb, t.Elem(), seen)
+	case reflect.Slice:
+		sb.WriteString("[]")
+		writeShape(sb, t.Elem(), seen)
+	case reflect.Array:
+		fmt.Fprintf(sb, "[%d]", t.Len())
+		writeShape(sb, t.Elem(), seen)
+	case reflect.Map:
+		sb.WriteString("map[")
+		writeShape(sb, t.Key(), seen)
+		sb.WriteString("]")
+		writeShape(sb, t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] {
+			sb.WriteString(t.String())
+			return
+		}
+		seen[t] = true
+		fields := make([]string, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			var fs strings.Builder
+			fmt.Fprintf(&fs, "%s %q ", f.Name, f.Tag)
+			writeShape(&fs, f.Type, seen)
+			fields = append(fields, fs.String())
+		}
+		// structs like time.Time only have unexported fields and marshal
+		// themselves, their name is the only stable description
+		if len(fields) == 0 {
+			sb.WriteString(t.String())
+			return
+		}
+		sb.WriteString("{" + strings.Join(fields, ";") + "}")
+	default:
+		sb.WriteString(t.Kind().String())
+	}
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipBytes(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
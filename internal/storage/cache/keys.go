@@ -8,3 +8,7 @@ func (s *Store) KeyProduct(ID int32) string {
 func (s *Store) KeyAllProducts() string {
 	return s.prefix + ":products:all"
 }
+
+func (s *Store) KeyPersistedQuery(hash string) string {
+	return s.prefix + ":apq:" + hash
+}
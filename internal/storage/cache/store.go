@@ -2,7 +2,7 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"go-graphql/internal/config"
 	"time"
 
@@ -10,34 +10,59 @@ import (
 )
 
 type Store struct {
-	client *redis.Client
-	prefix string
+	client   redis.UniversalClient
+	prefix   string
+	envelope envelope
+	counters counters
 }
 
-func NewCacheStore(client *redis.Client, cfg *config.Config) *Store {
+func NewCacheStore(client redis.UniversalClient, cfg *config.Config) (*Store, error) {
+	codec, err := CodecByName(cfg.Redis.Codec)
+	if err != nil {
+		return nil, err
+	}
 	return &Store{
 		client: client,
 		prefix: cfg.Redis.Prefix,
-	}
+		envelope: envelope{
+			codec:             codec,
+			version:           cfg.Redis.Version,
+			compressThreshold: cfg.Redis.CompressThreshold,
+		},
+	}, nil
 }
 
 // Set stores any serializable value with a TTL, ttl is in minutes
 func (r *Store) Set(ctx context.Context, key string, value interface{}, ttl int) error {
 	ttlDuration := time.Duration(ttl) * time.Minute
-	data, err := json.Marshal(value)
+	data, err := r.envelope.encode(value)
 	if err != nil {
 		return err
 	}
 	return r.client.Set(ctx, key, data, ttlDuration).Err()
 }
 
-// Get retrieves a value and un marshals it into dest (must be a pointer)
+// Get retrieves a value and un marshals it into dest (must be a pointer),
+// entries written with another schema are evicted and reported as ErrStaleEntry
 func (r *Store) Get(ctx context.Context, key string, dest interface{}) error {
 	data, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
+		if err == redis.Nil {
+			r.counters.misses.Add(1)
+		}
 		return err
 	}
-	return json.Unmarshal(data, dest)
+	err = r.envelope.decode(data, dest)
+	if errors.Is(err, ErrStaleEntry) || errors.Is(err, ErrCorruptEntry) {
+		r.counters.misses.Add(1)
+		r.counters.evicted.Add(1)
+		r.client.Del(ctx, key)
+		return err
+	}
+	if err == nil {
+		r.counters.hits.Add(1)
+	}
+	return err
 }
 
 // Delete removes a key from the cache
@@ -0,0 +1,61 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-graphql/internal/storage/sql/sqlc"
+)
+
+// ProductFixture is a product row, ID is required so loading the same file
+// twice updates the rows instead of duplicating them
+type ProductFixture struct {
+	ID          int32  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Price       int64  `json:"price"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+var productEntity = Entity{
+	Key:  "products",
+	Load: loadProducts,
+	Truncate: func(ctx context.Context, q *sqlc.Queries) error {
+		return q.TruncateProducts(ctx)
+	},
+}
+
+func loadProducts(ctx context.Context, q *sqlc.Queries, raw json.RawMessage) (int, error) {
+	var fixtures []ProductFixture
+	if err := json.Unmarshal(raw, &fixtures); err != nil {
+		return 0, err
+	}
+	written := 0
+	for _, f := range fixtures {
+		if f.ID <= 0 {
+			return 0, fmt.Errorf("product %q: id must be greater than 0", f.Name)
+		}
+		isActive := true
+		if f.IsActive != nil {
+			isActive = *f.IsActive
+		}
+		// rows already matching their fixture are left untouched, their
+		// version and so their ETag do not change
+		n, err := q.UpsertProduct(ctx, sqlc.UpsertProductParams{
+			ID:                 f.ID,
+			ProductName:        f.Name,
+			ProductDescription: f.Description,
+			Price:              f.Price,
+			IsActive:           isActive,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("product %d: %w", f.ID, err)
+		}
+		written += int(n)
+	}
+	// explicit ids do not advance the serial, keep it ahead of them
+	if err := q.SyncProductIDSequence(ctx); err != nil {
+		return 0, err
+	}
+	return written, nil
+}
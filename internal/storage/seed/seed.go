@@ -0,0 +1,151 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-graphql/internal/config"
+	"go-graphql/internal/storage"
+	"go-graphql/internal/storage/sql/sqlc"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrResetNotAllowed guards production data from --reset
+var ErrResetNotAllowed = errors.New("reset is only allowed in test and development environments")
+
+// Entity loads the fixtures found under one top-level key of a fixture file.
+// Load receives the raw JSON of that key and must be idempotent, it returns
+// the number of rows inserted or changed.
+type Entity struct {
+	Key      string
+	Load     func(ctx context.Context, q *sqlc.Queries, raw json.RawMessage) (int, error)
+	Truncate func(ctx context.Context, q *sqlc.Queries) error
+}
+
+// Entities lists every entity the loader knows, in dependency order: they
+// are loaded first to last and truncated last to first
+var Entities = []Entity{
+	productEntity,
+}
+
+// Loader writes fixture files into the database inside one transaction
+type Loader struct {
+	tx       *storage.TxManager
+	cfg      *config.Config
+	entities []Entity
+}
+
+func NewLoader(tx *storage.TxManager, cfg *config.Config) *Loader {
+	return &Loader{tx: tx, cfg: cfg, entities: Entities}
+}
+
+// Result counts the rows inserted or changed per entity key, rows already
+// matching their fixture are not counted
+type Result map[string]int
+
+// LoadFiles loads every .yaml, .yml and .json file in paths, directories are
+// expanded to the fixture files they contain. With reset the tables are
+// truncated first, which is refused outside test and development.
+func (l *Loader) LoadFiles(ctx context.Context, reset bool, paths ...string) (Result, error) {
+	if reset && !l.cfg.IsTest() && !l.cfg.IsDevelopment() {
+		return nil, ErrResetNotAllowed
+	}
+	files, err := fixtureFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]map[string]json.RawMessage, 0, len(files))
+	for _, file := range files {
+		doc, err := parseFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	result := Result{}
+	err = l.tx.WithinTx(ctx, func(ctx context.Context, q *sqlc.Queries) error {
+		if reset {
+			for i := len(l.entities) - 1; i >= 0; i-- {
+				if err := l.entities[i].Truncate(ctx, q); err != nil {
+					return fmt.Errorf("truncate %s: %w", l.entities[i].Key, err)
+				}
+			}
+		}
+		for _, entity := range l.entities {
+			for _, doc := range docs {
+				raw, ok := doc[entity.Key]
+				if !ok {
+					continue
+				}
+				n, err := entity.Load(ctx, q, raw)
+				if err != nil {
+					return fmt.Errorf("load %s: %w", entity.Key, err)
+				}
+				result[entity.Key] += n
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// parseFile decodes a YAML or JSON fixture file into its top-level keys,
+// YAML being a superset of JSON both go through the YAML decoder
+func parseFile(path string) (map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, entity := range Entities {
+		known[entity.Key] = true
+	}
+	raw := make(map[string]json.RawMessage, len(doc))
+	for key, value := range doc {
+		if !known[key] {
+			return nil, fmt.Errorf("unknown fixture key %q", key)
+		}
+		if raw[key], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return raw, nil
+}
+
+func fixtureFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var dirFiles []string
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				dirFiles = append(dirFiles, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
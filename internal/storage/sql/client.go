@@ -1,16 +1,109 @@
 package sql
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
 	"go-graphql/internal/config"
 	"go-graphql/internal/storage/sql/sqlc"
-	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
-func InitialDB(cfg *config.Config) sqlc.DBTX {
-	sql, err := sql.Open("postgres", cfg.Database.DSN)
+// InitialDB opens the pgx connection pool configured by DatabaseCfg, pings
+// it on start when enabled and closes it when the app stops
+func InitialDB(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger, tp trace.TracerProvider) (*pgxpool.Pool, error) {
+	log = log.Named("sql")
+	pool, err := NewPool(context.Background(), cfg.Database, NewQueryTracer(tp, "primary"))
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if !cfg.Database.PingOnStart {
+				return nil
+			}
+			return pingWithRetry(ctx, pool, cfg.Database, log)
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("Closing database connections")
+			pool.Close()
+			return nil
+		},
+	})
+	return pool, nil
+}
+
+// NewPool opens a pgx pool configured by DatabaseCfg outside of fx, for
+// commands that manage the pool lifetime themselves, tracer may be nil
+func NewPool(ctx context.Context, db config.DatabaseCfg, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
+	poolCfg, err := poolConfig(db)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	poolCfg.ConnConfig.Tracer = tracer
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return pool, nil
+}
+
+// NewDBTX exposes the primary and its replicas to sqlc through the router
+func NewDBTX(router *Router) sqlc.DBTX {
+	return router
+}
+
+func poolConfig(db config.DatabaseCfg) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(db.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("parse DATABASE_DSN: %w", err)
+	}
+	if db.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(db.MaxOpenConns)
+	}
+	poolCfg.MinConns = int32(db.MinConns)
+	if db.ConnMaxLifetime > 0 {
+		poolCfg.MaxConnLifetime = time.Duration(db.ConnMaxLifetime) * time.Second
+	}
+	if db.ConnMaxIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = time.Duration(db.ConnMaxIdleTime) * time.Second
+	}
+	// every session started by the pool inherits the statement timeout
+	if db.StatementTimeout > 0 {
+		poolCfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.Itoa(db.StatementTimeout)
+	}
+	return poolCfg, nil
+}
+
+func pingWithRetry(ctx context.Context, pool *pgxpool.Pool, cfg config.DatabaseCfg, log *zap.Logger) error {
+	backoff := time.Duration(cfg.PingBackoff) * time.Millisecond
+	var err error
+	for attempt := 0; attempt <= cfg.PingRetries; attempt++ {
+		if err = pool.Ping(ctx); err == nil {
+			log.Info("Database is reachable", zap.Int("attempt", attempt+1))
+			return nil
+		}
+		if attempt == cfg.PingRetries {
+			break
+		}
+		log.Warn("Database ping failed, retrying",
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("ping database: %w", ctx.Err())
+		}
+		backoff *= 2
 	}
-	return sql
+	return fmt.Errorf("ping database after %d attempts: %w", cfg.PingRetries+1, err)
 }
@@ -0,0 +1,31 @@
+package sql
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads are served by the primary. The
+// flag is mutable: MarkWritten sets it after a commit, so later reads in the
+// same request see their own writes.
+func WithPrimary(ctx context.Context, primary bool) context.Context {
+	flag := &atomic.Bool{}
+	flag.Store(primary)
+	return context.WithValue(ctx, primaryKey{}, flag)
+}
+
+// MarkWritten routes the remaining reads of the request carried by ctx to
+// the primary, it is a no-op when ctx was not prepared with WithPrimary
+func MarkWritten(ctx context.Context) {
+	if flag, ok := ctx.Value(primaryKey{}).(*atomic.Bool); ok {
+		flag.Store(true)
+	}
+}
+
+// PrimaryRequested reports whether reads for ctx must go to the primary
+func PrimaryRequested(ctx context.Context) bool {
+	flag, ok := ctx.Value(primaryKey{}).(*atomic.Bool)
+	return ok && flag.Load()
+}
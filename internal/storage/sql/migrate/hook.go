@@ -1,10 +1,118 @@
 package migrate
 
-import "go-graphql/internal/config"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-graphql/internal/config"
+	"sync"
+	"time"
 
-func RunMigrations(runner *Runner, cfg *config.Config) {
-	if cfg.IsTest() {
-		return
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const schemaPollInterval = 5 * time.Second
+
+// Readiness stays false until the schema matches the latest embedded
+// migration, so traffic is held back instead of killing the process
+type Readiness struct {
+	mu    sync.RWMutex
+	ready bool
+	err   error
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{err: errors.New("migrations have not run yet")}
+}
+
+// Ready reports whether the schema is up to date, err explains why not
+func (r *Readiness) Ready() (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.ready, r.err
+}
+
+func (r *Readiness) set(ready bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ready, r.err = ready, err
+}
+
+// RunMigrations applies migrations in the background when
+// DatabaseCfg.AutoMigrate is enabled, otherwise it waits for an external
+// `cmd/migrate up` to bring the schema to the latest version
+func RunMigrations(lc fx.Lifecycle, runner *Runner, readiness *Readiness, cfg *config.Config, log *zap.Logger) {
+	log = log.Named("migrate")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				if cfg.Database.AutoMigrate {
+					migrateOnStart(ctx, runner, readiness, log)
+					return
+				}
+				waitForSchema(ctx, runner, readiness, log)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+func migrateOnStart(ctx context.Context, runner *Runner, readiness *Readiness, log *zap.Logger) {
+	for {
+		err := runner.Up(ctx)
+		if err == nil {
+			log.Info("✅ Migrations applied successfully")
+			readiness.set(true, nil)
+			return
+		}
+		readiness.set(false, err)
+		var dirty *DirtyError
+		if errors.As(err, &dirty) {
+			log.Error("Migrations blocked by dirty schema", zap.Uint("version", dirty.Version), zap.Error(err))
+			return
+		}
+		// another instance may hold the lock or the database may be down,
+		// Up is a no-op once the schema is at the latest version
+		log.Error("Migrations failed, retrying", zap.Duration("backoff", schemaPollInterval), zap.Error(err))
+		select {
+		case <-time.After(schemaPollInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func waitForSchema(ctx context.Context, runner *Runner, readiness *Readiness, log *zap.Logger) {
+	for {
+		status, err := runner.Status()
+		switch {
+		case err != nil:
+			readiness.set(false, err)
+		case status.Dirty:
+			readiness.set(false, &DirtyError{Version: status.Version})
+		case !status.Applied || status.Version < status.Latest:
+			readiness.set(false, fmt.Errorf("schema is at version %d, waiting for migrations up to %d", status.Version, status.Latest))
+		default:
+			log.Info("Schema is up to date", zap.Uint("version", status.Version))
+			readiness.set(true, nil)
+			return
+		}
+		select {
+		case <-time.After(schemaPollInterval):
+		case <-ctx.Done():
+			return
+		}
 	}
-	runner.Run()
 }
@@ -1,33 +1,286 @@
 package migrate
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"go-graphql/internal/config"
-	"log"
+	"go-graphql/internal/storage/sql/migrations"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
 )
 
+// Dir is where new migrations are created, relative to the project root
+const Dir = "internal/storage/sql/migrations"
+
+// lockID is the Postgres advisory lock serializing migrations across
+// instances starting at the same time
+const lockID int64 = 0x676f2d6d6967 // "go-mig"
+
+const lockPollInterval = 500 * time.Millisecond
+
 type Runner struct {
-	DSN string
+	DSN     string
+	connDSN string
+	timeout time.Duration
+}
+
+// DirtyError is returned when a previous migration failed half way, the
+// schema must be fixed by hand before migrating again
+type DirtyError struct {
+	Version uint
+}
+
+func (e *DirtyError) Error() string {
+	return fmt.Sprintf(
+		"database is dirty at version %d: fix the failed migration by hand, "+
+			"then run `go run ./cmd/migrate force %d`", e.Version, e.Version)
+}
+
+// Status describes the schema version currently applied to the database
+type Status struct {
+	Version uint
+	Dirty   bool
+	Applied bool // false when no migration ran yet
+	Latest  uint // latest migration embedded in the binary
 }
 
 // internal/db/migrate/runner.go
 func NewRunner(cfg *config.Config) *Runner {
-	return &Runner{DSN: cfg.Database.DSN}
+	return &Runner{
+		DSN:     pgxDSN(cfg.Database.DSN),
+		connDSN: cfg.Database.DSN,
+		timeout: time.Duration(cfg.Database.MigrateTimeout) * time.Second,
+	}
+}
+
+// Up applies every pending migration while holding the advisory lock, so
+// only one instance migrates at a time. Waiting for the lock and running
+// the migrations must fit in DatabaseCfg.MigrateTimeout, on timeout the
+// migration in progress finishes and the remaining ones are skipped.
+func (r *Runner) Up(ctx context.Context) error {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	conn, err := pgx.Connect(ctx, r.connDSN)
+	if err != nil {
+		return fmt.Errorf("migration lock connection error: %w", err)
+	}
+	defer conn.Close(context.Background())
+	if err := acquireLock(ctx, conn); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	return r.with(func(m *migrate.Migrate) error {
+		version, dirty, err := m.Version()
+		if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+			return err
+		}
+		if dirty {
+			return &DirtyError{Version: version}
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- ignoreNoChange(m.Up()) }()
+		select {
+		case err := <-done:
+			return dirtyOr(m, err)
+		case <-ctx.Done():
+			m.GracefulStop <- true
+			<-done
+			return fmt.Errorf("migrations stopped after %s: %w", r.timeout, ctx.Err())
+		}
+	})
+}
+
+func acquireLock(ctx context.Context, conn *pgx.Conn) error {
+	for {
+		var locked bool
+		if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", lockID).Scan(&locked); err != nil {
+			// the deadline can also expire while a lock attempt is in flight
+			if ctx.Err() != nil {
+				return fmt.Errorf("waiting for migration lock held by another instance: %w", ctx.Err())
+			}
+			return fmt.Errorf("migration lock error: %w", err)
+		}
+		if locked {
+			return nil
+		}
+		select {
+		case <-time.After(lockPollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for migration lock held by another instance: %w", ctx.Err())
+		}
+	}
+}
+
+// dirtyOr reports a failed migration as a DirtyError when it left the
+// schema dirty
+func dirtyOr(m *migrate.Migrate, err error) error {
+	if err == nil {
+		return nil
+	}
+	if version, dirty, vErr := m.Version(); vErr == nil && dirty {
+		return fmt.Errorf("%w: %v", &DirtyError{Version: version}, err)
+	}
+	return err
+}
+
+// Down rolls back the last n migrations
+func (r *Runner) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("down expects a positive number of migrations, got %d", n)
+	}
+	return r.with(func(m *migrate.Migrate) error {
+		return ignoreNoChange(m.Steps(-n))
+	})
+}
+
+// Goto migrates up or down to the given version, version 0 has no
+// migration file and rolls back every migration
+func (r *Runner) Goto(version uint) error {
+	latest, err := LatestVersion()
+	if err != nil {
+		return err
+	}
+	if version > latest {
+		return fmt.Errorf("goto expects a version up to the latest migration %d, got %d", latest, version)
+	}
+	return r.with(func(m *migrate.Migrate) error {
+		if version == 0 {
+			return ignoreNoChange(m.Down())
+		}
+		return ignoreNoChange(m.Migrate(version))
+	})
+}
+
+// Force sets the version without running migrations and clears the dirty
+// flag, used to recover after a failed migration was fixed by hand
+func (r *Runner) Force(version int) error {
+	return r.with(func(m *migrate.Migrate) error {
+		return m.Force(version)
+	})
+}
+
+// Status returns the applied version and the latest embedded one
+func (r *Runner) Status() (Status, error) {
+	var status Status
+	latest, err := LatestVersion()
+	if err != nil {
+		return status, err
+	}
+	status.Latest = latest
+	err = r.with(func(m *migrate.Migrate) error {
+		version, dirty, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		status.Version, status.Dirty, status.Applied = version, dirty, true
+		return nil
+	})
+	return status, err
 }
 
-func (r *Runner) Run() {
-	m, err := migrate.New(
-		"file://internal/storage/sql/migrations",
-		r.DSN,
-	)
+func (r *Runner) with(fn func(m *migrate.Migrate) error) error {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return fmt.Errorf("migration source error: %w", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", src, r.DSN)
 	if err != nil {
-		log.Fatalf("migration init error: %v", err)
+		return fmt.Errorf("migration init error: %w", err)
+	}
+	defer m.Close()
+	return fn(m)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
 	}
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("migration run error: %v", err)
+	return err
+}
+
+var migrationName = regexp.MustCompile(`^(\d+)_.+\.(up|down)\.sql$`)
+
+// LatestVersion returns the highest migration version embedded in the binary
+func LatestVersion() (uint, error) {
+	entries, err := migrations.FS.ReadDir(".")
+	if err != nil {
+		return 0, err
+	}
+	var latest uint
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes an empty up/down migration pair into dir, numbered after
+// the latest migration found there, and returns the created paths
+func Create(dir, name string) ([]string, error) {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migration name is empty")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var latest uint64
+	for _, entry := range entries {
+		if match := migrationName.FindStringSubmatch(entry.Name()); match != nil {
+			if version, _ := strconv.ParseUint(match[1], 10, 64); version > latest {
+				latest = version
+			}
+		}
+	}
+
+	var paths []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%04d_%s.%s.sql", latest+1, name, direction))
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// pgxDSN selects the pgx/v5 migrate driver, registered under pgx5://
+func pgxDSN(dsn string) string {
+	for _, scheme := range []string{"postgresql://", "postgres://"} {
+		if strings.HasPrefix(dsn, scheme) {
+			return "pgx5://" + strings.TrimPrefix(dsn, scheme)
+		}
 	}
-	log.Println("✅ Migrations applied successfully")
+	return dsn
 }
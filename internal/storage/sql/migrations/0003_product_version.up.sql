@@ -0,0 +1,2 @@
+-- incremented on every update, HTTP ETags are derived from it
+ALTER TABLE products ADD COLUMN version INT DEFAULT 1 NOT NULL;
@@ -0,0 +1,193 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"go-graphql/internal/config"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const replicaPingTimeout = 2 * time.Second
+
+// Router is a sqlc.DBTX that sends read-only statements to healthy
+// replicas in round-robin order and everything else to the primary.
+// Requests flagged with WithPrimary, or that already wrote in this request,
+// always read from the primary.
+type Router struct {
+	primary  *pgxpool.Pool
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	pool    *pgxpool.Pool
+	host    string
+	healthy atomic.Bool
+}
+
+// ReplicaStatus reports the last health check result of a replica
+type ReplicaStatus struct {
+	Host    string `json:"host"`
+	Healthy bool   `json:"healthy"`
+}
+
+func NewRouter(lc fx.Lifecycle, primary *pgxpool.Pool, cfg *config.Config, log *zap.Logger, tp trace.TracerProvider) (*Router, error) {
+	log = log.Named("sql")
+	r := &Router{primary: primary}
+	for _, dsn := range cfg.Database.ReplicaDSNs {
+		pool, err := NewPool(context.Background(), config.DatabaseCfg{
+			DSN:              dsn,
+			MaxOpenConns:     cfg.Database.MaxOpenConns,
+			MinConns:         cfg.Database.MinConns,
+			ConnMaxLifetime:  cfg.Database.ConnMaxLifetime,
+			ConnMaxIdleTime:  cfg.Database.ConnMaxIdleTime,
+			StatementTimeout: cfg.Database.StatementTimeout,
+		}, NewQueryTracer(tp, "replica"))
+		if err != nil {
+			return nil, fmt.Errorf("replica: %w", err)
+		}
+		r.replicas = append(r.replicas, &replica{pool: pool, host: pool.Config().ConnConfig.Host})
+	}
+	if len(r.replicas) == 0 {
+		return r, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	interval := time.Duration(cfg.Database.ReplicaCheck) * time.Second
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			r.checkReplicas(ctx, log)
+			go r.watchReplicas(ctx, interval, log)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			for _, rep := range r.replicas {
+				rep.pool.Close()
+			}
+			return nil
+		},
+	})
+	return r, nil
+}
+
+func (r *Router) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	return r.primary.Exec(ctx, query, args...)
+}
+
+func (r *Router) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	return r.reader(ctx, query).Query(ctx, query, args...)
+}
+
+func (r *Router) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	return r.reader(ctx, query).QueryRow(ctx, query, args...)
+}
+
+// Pools returns the primary pool and the replica pools, named replica_1,
+// replica_2... in DatabaseCfg.ReplicaDSNs order
+func (r *Router) Pools() map[string]*pgxpool.Pool {
+	pools := map[string]*pgxpool.Pool{"primary": r.primary}
+	for i, rep := range r.replicas {
+		pools[fmt.Sprintf("replica_%d", i+1)] = rep.pool
+	}
+	return pools
+}
+
+// Replicas returns the health of every configured replica
+func (r *Router) Replicas() []ReplicaStatus {
+	statuses := make([]ReplicaStatus, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		statuses = append(statuses, ReplicaStatus{Host: rep.host, Healthy: rep.healthy.Load()})
+	}
+	return statuses
+}
+
+func (r *Router) reader(ctx context.Context, query string) *pgxpool.Pool {
+	if len(r.replicas) == 0 || PrimaryRequested(ctx) || !isReadOnly(query) {
+		return r.primary
+	}
+	// rotate over the healthy replicas only, skipping unhealthy ones in a
+	// rotation over all of them would double the load of their successor
+	healthy := 0
+	for _, rep := range r.replicas {
+		if rep.healthy.Load() {
+			healthy++
+		}
+	}
+	if healthy == 0 {
+		return r.primary
+	}
+	k := r.next.Add(1) % uint64(healthy)
+	for _, rep := range r.replicas {
+		if !rep.healthy.Load() {
+			continue
+		}
+		if k == 0 {
+			return rep.pool
+		}
+		k--
+	}
+	// a replica turned unhealthy while counting
+	return r.primary
+}
+
+func (r *Router) watchReplicas(ctx context.Context, interval time.Duration, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.checkReplicas(ctx, log)
+		}
+	}
+}
+
+func (r *Router) checkReplicas(ctx context.Context, log *zap.Logger) {
+	for _, rep := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, replicaPingTimeout)
+		err := rep.pool.Ping(pingCtx)
+		cancel()
+		healthy := err == nil
+		if rep.healthy.Swap(healthy) != healthy {
+			if healthy {
+				log.Info("Replica is healthy", zap.String("host", rep.host))
+			} else {
+				log.Warn("Replica is unhealthy, routing reads elsewhere", zap.String("host", rep.host), zap.Error(err))
+			}
+		}
+	}
+}
+
+var lockingClause = regexp.MustCompile(`(?i)\bFOR\s+(UPDATE|SHARE|NO\s+KEY\s+UPDATE|KEY\s+SHARE)\b`)
+
+// isReadOnly reports whether query is a plain SELECT, sqlc prefixes every
+// statement with a "-- name:" comment which is skipped
+func isReadOnly(query string) bool {
+	for {
+		query = strings.TrimSpace(query)
+		if !strings.HasPrefix(query, "--") {
+			break
+		}
+		end := strings.IndexByte(query, '\n')
+		if end < 0 {
+			return false
+		}
+		query = query[end+1:]
+	}
+	if len(query) < len("SELECT") || !strings.EqualFold(query[:len("SELECT")], "SELECT") {
+		return false
+	}
+	return !lockingClause.MatchString(query)
+}
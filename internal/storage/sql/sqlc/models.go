@@ -6,8 +6,22 @@ package sqlc
 
 import (
 	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiKey struct {
+	ID         int32
+	KeyName    string
+	KeyPrefix  string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  pgtype.Timestamp
+	LastUsedAt pgtype.Timestamp
+	RevokedAt  pgtype.Timestamp
+	CreatedAt  time.Time
+}
+
 type Product struct {
 	ID                 int32
 	ProductName        string
@@ -15,4 +29,5 @@ type Product struct {
 	Price              int64
 	IsActive           bool
 	CreatedAt          time.Time
+	Version            int32
 }
@@ -7,7 +7,8 @@ package sqlc
 
 import (
 	"context"
-	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const countProductsWithFilters = `-- name: CountProductsWithFilters :one
@@ -23,16 +24,16 @@ WHERE
 `
 
 type CountProductsWithFiltersParams struct {
-	ID                 sql.NullInt32
-	ProductName        sql.NullString
-	MinPrice           sql.NullInt64
-	MaxPrice           sql.NullInt64
-	IsActive           sql.NullBool
-	ProductDescription sql.NullString
+	ID                 pgtype.Int4
+	ProductName        pgtype.Text
+	MinPrice           pgtype.Int8
+	MaxPrice           pgtype.Int8
+	IsActive           pgtype.Bool
+	ProductDescription pgtype.Text
 }
 
 func (q *Queries) CountProductsWithFilters(ctx context.Context, arg CountProductsWithFiltersParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countProductsWithFilters,
+	row := q.db.QueryRow(ctx, countProductsWithFilters,
 		arg.ID,
 		arg.ProductName,
 		arg.MinPrice,
@@ -48,7 +49,7 @@ func (q *Queries) CountProductsWithFilters(ctx context.Context, arg CountProduct
 const createProduct = `-- name: CreateProduct :one
 INSERT INTO products (product_name, product_description, price, is_active)
 VALUES ($1, $2, $3, $4)
-RETURNING id, product_name, product_description, price, is_active, created_at
+RETURNING id, product_name, product_description, price, is_active, created_at, version
 `
 
 type CreateProductParams struct {
@@ -59,7 +60,7 @@ type CreateProductParams struct {
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, createProduct,
+	row := q.db.QueryRow(ctx, createProduct,
 		arg.ProductName,
 		arg.ProductDescription,
 		arg.Price,
@@ -73,6 +74,7 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.Price,
 		&i.IsActive,
 		&i.CreatedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -82,16 +84,16 @@ DELETE FROM products WHERE id = $1
 `
 
 func (q *Queries) DeleteProduct(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteProduct, id)
+	_, err := q.db.Exec(ctx, deleteProduct, id)
 	return err
 }
 
 const getProduct = `-- name: GetProduct :one
-SELECT id, product_name, product_description, price, is_active, created_at FROM products WHERE id = $1
+SELECT id, product_name, product_description, price, is_active, created_at, version FROM products WHERE id = $1
 `
 
 func (q *Queries) GetProduct(ctx context.Context, id int32) (Product, error) {
-	row := q.db.QueryRowContext(ctx, getProduct, id)
+	row := q.db.QueryRow(ctx, getProduct, id)
 	var i Product
 	err := row.Scan(
 		&i.ID,
@@ -100,16 +102,17 @@ func (q *Queries) GetProduct(ctx context.Context, id int32) (Product, error) {
 		&i.Price,
 		&i.IsActive,
 		&i.CreatedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT id, product_name, product_description, price, is_active, created_at FROM products ORDER BY created_at DESC
+SELECT id, product_name, product_description, price, is_active, created_at, version FROM products ORDER BY created_at DESC, id DESC
 `
 
 func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, listProducts)
+	rows, err := q.db.Query(ctx, listProducts)
 	if err != nil {
 		return nil, err
 	}
@@ -124,14 +127,12 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 			&i.Price,
 			&i.IsActive,
 			&i.CreatedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -139,7 +140,7 @@ func (q *Queries) ListProducts(ctx context.Context) ([]Product, error) {
 }
 
 const listProductsWithFilters = `-- name: ListProductsWithFilters :many
-SELECT id, product_name, product_description, price, is_active, created_at
+SELECT id, product_name, product_description, price, is_active, created_at, version
 FROM products
 WHERE
   ($1::int IS NULL OR id = $1)
@@ -148,30 +149,58 @@ WHERE
   AND ($4::bigint IS NULL OR price <= $4)
   AND ($5::bool IS NULL OR is_active = $5)
   AND ($6::text IS NULL OR product_description ILIKE '%' || $6 || '%')
-ORDER BY id
-LIMIT $8
-OFFSET $7
+  AND ($7::int IS NULL OR CASE $8::text
+    WHEN 'id' THEN id > $7
+    WHEN '-id' THEN id < $7
+    WHEN 'name' THEN (product_name, id) > ($9::text, $7)
+    WHEN '-name' THEN (product_name, id) < ($9::text, $7)
+    WHEN 'price' THEN (price, id) > ($10::bigint, $7)
+    WHEN '-price' THEN (price, id) < ($10::bigint, $7)
+    WHEN 'created_at' THEN (created_at, id) > ($11::timestamp, $7)
+    WHEN '-created_at' THEN (created_at, id) < ($11::timestamp, $7)
+  END)
+ORDER BY
+  CASE WHEN $8 = 'name' THEN product_name END ASC,
+  CASE WHEN $8 = '-name' THEN product_name END DESC,
+  CASE WHEN $8 = 'price' THEN price END ASC,
+  CASE WHEN $8 = '-price' THEN price END DESC,
+  CASE WHEN $8 = 'created_at' THEN created_at END ASC,
+  CASE WHEN $8 = '-created_at' THEN created_at END DESC,
+  CASE WHEN $8 LIKE '-%' THEN id END DESC,
+  id ASC
+LIMIT $13
+OFFSET $12
 `
 
 type ListProductsWithFiltersParams struct {
-	ID                 sql.NullInt32
-	ProductName        sql.NullString
-	MinPrice           sql.NullInt64
-	MaxPrice           sql.NullInt64
-	IsActive           sql.NullBool
-	ProductDescription sql.NullString
-	Offset             sql.NullInt64
-	Limit              sql.NullInt64
+	ID                 pgtype.Int4
+	ProductName        pgtype.Text
+	MinPrice           pgtype.Int8
+	MaxPrice           pgtype.Int8
+	IsActive           pgtype.Bool
+	ProductDescription pgtype.Text
+	AfterID            pgtype.Int4
+	Sort               string
+	AfterName          pgtype.Text
+	AfterPrice         pgtype.Int8
+	AfterCreatedAt     pgtype.Timestamp
+	Offset             pgtype.Int8
+	Limit              pgtype.Int8
 }
 
 func (q *Queries) ListProductsWithFilters(ctx context.Context, arg ListProductsWithFiltersParams) ([]Product, error) {
-	rows, err := q.db.QueryContext(ctx, listProductsWithFilters,
+	rows, err := q.db.Query(ctx, listProductsWithFilters,
 		arg.ID,
 		arg.ProductName,
 		arg.MinPrice,
 		arg.MaxPrice,
 		arg.IsActive,
 		arg.ProductDescription,
+		arg.AfterID,
+		arg.Sort,
+		arg.AfterName,
+		arg.AfterPrice,
+		arg.AfterCreatedAt,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -189,25 +218,41 @@ func (q *Queries) ListProductsWithFilters(ctx context.Context, arg ListProductsW
 			&i.Price,
 			&i.IsActive,
 			&i.CreatedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+const syncProductIDSequence = `-- name: SyncProductIDSequence :exec
+SELECT setval(pg_get_serial_sequence('products', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM products
+`
+
+func (q *Queries) SyncProductIDSequence(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, syncProductIDSequence)
+	return err
+}
+
+const truncateProducts = `-- name: TruncateProducts :exec
+TRUNCATE products RESTART IDENTITY CASCADE
+`
+
+func (q *Queries) TruncateProducts(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, truncateProducts)
+	return err
+}
+
 const updateProduct = `-- name: UpdateProduct :one
 UPDATE products
-SET product_name = $2, product_description = $3, price = $4, is_active = $5
+SET product_name = $2, product_description = $3, price = $4, is_active = $5, version = version + 1
 WHERE id = $1
-RETURNING id, product_name, product_description, price, is_active, created_at
+RETURNING id, product_name, product_description, price, is_active, created_at, version
 `
 
 type UpdateProductParams struct {
@@ -219,7 +264,7 @@ type UpdateProductParams struct {
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
-	row := q.db.QueryRowContext(ctx, updateProduct,
+	row := q.db.QueryRow(ctx, updateProduct,
 		arg.ID,
 		arg.ProductName,
 		arg.ProductDescription,
@@ -234,6 +279,42 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.Price,
 		&i.IsActive,
 		&i.CreatedAt,
+		&i.Version,
 	)
 	return i, err
 }
+
+const upsertProduct = `-- name: UpsertProduct :execrows
+INSERT INTO products (id, product_name, product_description, price, is_active)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE
+SET product_name = EXCLUDED.product_name,
+    product_description = EXCLUDED.product_description,
+    price = EXCLUDED.price,
+    is_active = EXCLUDED.is_active,
+    version = products.version + 1
+WHERE (products.product_name, products.product_description, products.price, products.is_active)
+    IS DISTINCT FROM (EXCLUDED.product_name, EXCLUDED.product_description, EXCLUDED.price, EXCLUDED.is_active)
+`
+
+type UpsertProductParams struct {
+	ID                 int32
+	ProductName        string
+	ProductDescription string
+	Price              int64
+	IsActive           bool
+}
+
+func (q *Queries) UpsertProduct(ctx context.Context, arg UpsertProductParams) (int64, error) {
+	result, err := q.db.Exec(ctx, upsertProduct,
+		arg.ID,
+		arg.ProductName,
+		arg.ProductDescription,
+		arg.Price,
+		arg.IsActive,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
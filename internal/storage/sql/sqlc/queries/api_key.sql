@@ -0,0 +1,19 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (key_name, key_prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_keys WHERE key_hash = $1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_keys ORDER BY id;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = timezone('utc', now())
+WHERE id = $1 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys SET last_used_at = timezone('utc', now()) WHERE id = $1;
@@ -7,11 +7,11 @@ RETURNING *;
 SELECT * FROM products WHERE id = $1;
 
 -- name: ListProducts :many
-SELECT * FROM products ORDER BY created_at DESC;
+SELECT * FROM products ORDER BY created_at DESC, id DESC;
 
 -- name: UpdateProduct :one
 UPDATE products
-SET product_name = $2, product_description = $3, price = $4, is_active = $5
+SET product_name = $2, product_description = $3, price = $4, is_active = $5, version = version + 1
 WHERE id = $1
 RETURNING *;
 
@@ -19,7 +19,7 @@ RETURNING *;
 DELETE FROM products WHERE id = $1;
 
 -- name: ListProductsWithFilters :many
-SELECT id, product_name, product_description, price, is_active, created_at
+SELECT id, product_name, product_description, price, is_active, created_at, version
 FROM products
 WHERE
   (sqlc.narg('id')::int IS NULL OR id = sqlc.narg('id'))
@@ -28,7 +28,25 @@ WHERE
   AND (sqlc.narg('max_price')::bigint IS NULL OR price <= sqlc.narg('max_price'))
   AND (sqlc.narg('is_active')::bool IS NULL OR is_active = sqlc.narg('is_active'))
   AND (sqlc.narg('product_description')::text IS NULL OR product_description ILIKE '%' || sqlc.narg('product_description') || '%')
-ORDER BY id
+  AND (sqlc.narg('after_id')::int IS NULL OR CASE sqlc.arg('sort')::text
+    WHEN 'id' THEN id > sqlc.narg('after_id')
+    WHEN '-id' THEN id < sqlc.narg('after_id')
+    WHEN 'name' THEN (product_name, id) > (sqlc.narg('after_name')::text, sqlc.narg('after_id'))
+    WHEN '-name' THEN (product_name, id) < (sqlc.narg('after_name')::text, sqlc.narg('after_id'))
+    WHEN 'price' THEN (price, id) > (sqlc.narg('after_price')::bigint, sqlc.narg('after_id'))
+    WHEN '-price' THEN (price, id) < (sqlc.narg('after_price')::bigint, sqlc.narg('after_id'))
+    WHEN 'created_at' THEN (created_at, id) > (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id'))
+    WHEN '-created_at' THEN (created_at, id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id'))
+  END)
+ORDER BY
+  CASE WHEN sqlc.arg('sort') = 'name' THEN product_name END ASC,
+  CASE WHEN sqlc.arg('sort') = '-name' THEN product_name END DESC,
+  CASE WHEN sqlc.arg('sort') = 'price' THEN price END ASC,
+  CASE WHEN sqlc.arg('sort') = '-price' THEN price END DESC,
+  CASE WHEN sqlc.arg('sort') = 'created_at' THEN created_at END ASC,
+  CASE WHEN sqlc.arg('sort') = '-created_at' THEN created_at END DESC,
+  CASE WHEN sqlc.arg('sort') LIKE '-%' THEN id END DESC,
+  id ASC
 LIMIT sqlc.narg('limit')
 OFFSET sqlc.narg('offset');
 
@@ -42,4 +60,22 @@ WHERE
   AND (sqlc.narg('min_price')::bigint IS NULL OR price >= sqlc.narg('min_price'))
   AND (sqlc.narg('max_price')::bigint IS NULL OR price <= sqlc.narg('max_price'))
   AND (sqlc.narg('is_active')::bool IS NULL OR is_active = sqlc.narg('is_active'))
-  AND (sqlc.narg('product_description')::text IS NULL OR product_description ILIKE '%' || sqlc.narg('product_description') || '%');
\ No newline at end of file
+  AND (sqlc.narg('product_description')::text IS NULL OR product_description ILIKE '%' || sqlc.narg('product_description') || '%');
+
+-- name: UpsertProduct :execrows
+INSERT INTO products (id, product_name, product_description, price, is_active)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (id) DO UPDATE
+SET product_name = EXCLUDED.product_name,
+    product_description = EXCLUDED.product_description,
+    price = EXCLUDED.price,
+    is_active = EXCLUDED.is_active,
+    version = products.version + 1
+WHERE (products.product_name, products.product_description, products.price, products.is_active)
+    IS DISTINCT FROM (EXCLUDED.product_name, EXCLUDED.product_description, EXCLUDED.price, EXCLUDED.is_active);
+
+-- name: SyncProductIDSequence :exec
+SELECT setval(pg_get_serial_sequence('products', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM products;
+
+-- name: TruncateProducts :exec
+TRUNCATE products RESTART IDENTITY CASCADE;
@@ -0,0 +1,11 @@
+CREATE TABLE api_keys (
+  id SERIAL PRIMARY KEY,
+  key_name TEXT NOT NULL,
+  key_prefix TEXT NOT NULL,
+  key_hash TEXT NOT NULL UNIQUE,
+  scopes TEXT[] NOT NULL,
+  expires_at TIMESTAMP,
+  last_used_at TIMESTAMP,
+  revoked_at TIMESTAMP,
+  created_at TIMESTAMP DEFAULT now() NOT NULL
+);
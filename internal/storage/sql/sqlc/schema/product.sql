@@ -4,6 +4,7 @@ CREATE TABLE products (
   product_description TEXT NOT NULL,
   price BIGINT NOT NULL,
   is_active BOOLEAN DEFAULT TRUE NOT NULL,
-  created_at TIMESTAMP DEFAULT now() NOT NULL
+  created_at TIMESTAMP DEFAULT now() NOT NULL,
+  version INT DEFAULT 1 NOT NULL
 );
 
@@ -0,0 +1,62 @@
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "go-graphql/internal/storage/sql"
+
+// queryTracer opens a span per query, named after the sqlc query
+type queryTracer struct {
+	tracer trace.Tracer
+	pool   string
+}
+
+// NewQueryTracer traces the queries of a pool, pool tells the primary
+// from the replicas in the span attributes
+func NewQueryTracer(tp trace.TracerProvider, pool string) pgx.QueryTracer {
+	return &queryTracer{tracer: tp.Tracer(tracerName), pool: pool}
+}
+
+func (t *queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = t.tracer.Start(ctx, "db "+queryName(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", data.SQL),
+			attribute.String("db.pool", t.pool),
+		),
+	)
+	return ctx
+}
+
+func (t *queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil && data.Err != pgx.ErrNoRows {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	span.End()
+}
+
+// queryName reads the "-- name: GetProduct :one" header sqlc writes before
+// every query, other statements are named after their first keyword
+func queryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(sql, "-- name: "); ok {
+		if name, _, found := strings.Cut(rest, " "); found {
+			return name
+		}
+	}
+	if keyword, _, _ := strings.Cut(sql, " "); keyword != "" {
+		return strings.ToUpper(keyword)
+	}
+	return "query"
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"go-graphql/internal/config"
+	"go-graphql/internal/pkg/logger"
+	sqlstore "go-graphql/internal/storage/sql"
+	"go-graphql/internal/storage/sql/sqlc"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const retryBackoff = 20 * time.Millisecond
+
+// TxManager runs sqlc queries inside transactions on the primary database
+type TxManager struct {
+	pool       *pgxpool.Pool
+	maxRetries int
+	log        *zap.Logger
+}
+
+func NewTxManager(pool *pgxpool.Pool, cfg *config.Config, log *zap.Logger) *TxManager {
+	return &TxManager{
+		pool:       pool,
+		maxRetries: cfg.Database.TxMaxRetries,
+		log:        log.Named("storage"),
+	}
+}
+
+type txKey struct{}
+
+type txState struct {
+	tx          pgx.Tx
+	afterCommit []func(context.Context)
+}
+
+// WithinTx runs fn in a serializable transaction and commits when fn
+// returns nil.
+//
+// The ctx passed to fn carries the transaction: calling WithinTx again with
+// it runs the nested fn in a savepoint, so a nested error only rolls back
+// the nested work. The outermost transaction is retried up to
+// DatabaseCfg.TxMaxRetries times on serialization failures and deadlocks,
+// fn must therefore be safe to run more than once.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context, q *sqlc.Queries) error) error {
+	if parent, ok := ctx.Value(txKey{}).(*txState); ok {
+		return withinSavepoint(ctx, parent, fn)
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = m.run(ctx, fn)
+		if !isRetryable(err) || attempt >= m.maxRetries {
+			return err
+		}
+		logger.FromContext(ctx, m.log).Warn("Transaction conflict, retrying", zap.Int("attempt", attempt+1), zap.Error(err))
+		select {
+		case <-time.After(time.Duration(attempt+1) * retryBackoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// AfterCommit registers fn to run once the outermost transaction carried by
+// ctx has committed, it is dropped when the transaction or the savepoint it
+// was registered in rolls back. Outside a transaction fn runs immediately.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		fn(ctx)
+		return
+	}
+	state.afterCommit = append(state.afterCommit, fn)
+}
+
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context, q *sqlc.Queries) error) error {
+	// serializable so concurrent writes fail with a retryable conflict
+	// instead of silently interleaving
+	tx, err := m.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.Serializable})
+	if err != nil {
+		return err
+	}
+	state := &txState{tx: tx}
+	if err := execute(ctx, state, fn); err != nil {
+		return err
+	}
+	sqlstore.MarkWritten(ctx)
+	for _, hook := range state.afterCommit {
+		hook(ctx)
+	}
+	return nil
+}
+
+func withinSavepoint(ctx context.Context, parent *txState, fn func(ctx context.Context, q *sqlc.Queries) error) error {
+	// Begin on a pgx.Tx creates a savepoint, Commit releases it
+	savepoint, err := parent.tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	state := &txState{tx: savepoint}
+	if err := execute(ctx, state, fn); err != nil {
+		return err
+	}
+	parent.afterCommit = append(parent.afterCommit, state.afterCommit...)
+	return nil
+}
+
+// execute runs fn on state.tx and commits it, rolling back on error or panic
+func execute(ctx context.Context, state *txState, fn func(ctx context.Context, q *sqlc.Queries) error) error {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = state.tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+	if err := fn(context.WithValue(ctx, txKey{}, state), sqlc.New(state.tx)); err != nil {
+		if rbErr := state.tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return state.tx.Commit(ctx)
+}
+
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgerrcode.SerializationFailure || pgErr.Code == pgerrcode.DeadlockDetected
+}
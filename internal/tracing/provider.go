@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go-graphql/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const defaultServiceName = "go-graphql"
+
+// NewTracerProvider builds the provider configured by TracingCfg, a no-op
+// provider when tracing is disabled. W3C trace context is propagated either
+// way so the trace of an upstream caller is kept in the logs.
+func NewTracerProvider(lc fx.Lifecycle, cfg *config.Config, log *zap.Logger) (trace.TracerProvider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+	if !cfg.Tracing.Enabled {
+		return noop.NewTracerProvider(), nil
+	}
+
+	exporter, closeOutput, err := newExporter(cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(ServiceName(cfg)),
+			semconv.DeploymentEnvironment(cfg.ENV),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			log.Info("Flushing traces")
+			err := tp.Shutdown(ctx)
+			if closeErr := closeOutput(); err == nil {
+				err = closeErr
+			}
+			return err
+		},
+	})
+	log.Info("Tracing enabled",
+		zap.String("exporter", cfg.Tracing.Exporter),
+		zap.Float64("sample_ratio", cfg.Tracing.SampleRatio),
+	)
+	return tp, nil
+}
+
+// ServiceName returns the service name reported in spans
+func ServiceName(cfg *config.Config) string {
+	if cfg.Tracing.ServiceName == "" {
+		return defaultServiceName
+	}
+	return cfg.Tracing.ServiceName
+}
+
+// newExporter returns the span exporter and a func closing its output
+func newExporter(tc config.TracingCfg) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+	switch tc.Exporter {
+	case config.TracingExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, noClose, err
+	case config.TracingExporterFile:
+		file, err := os.OpenFile(tc.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open TRACING_FILE: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(io.Writer(file)))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file.Close, nil
+	default:
+		var opts []otlptracehttp.Option
+		if tc.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(tc.Endpoint))
+		}
+		if tc.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		return exporter, noClose, err
+	}
+}
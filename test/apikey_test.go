@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	apikey "go-graphql/internal/apikey/service"
+	"go-graphql/internal/config"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeKeys authenticates the keys of its map, others are invalid
+type fakeKeys map[string]apikey.Principal
+
+func (f fakeKeys) Authenticate(_ context.Context, key string) (apikey.Principal, error) {
+	if key == "broken" {
+		return apikey.Principal{}, errors.New("database unavailable")
+	}
+	p, ok := f[key]
+	if !ok {
+		return apikey.Principal{}, apikey.ErrInvalidKey
+	}
+	return p, nil
+}
+
+func newAPIKeyEngine(anonymous bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	keys := fakeKeys{
+		"reader": {KeyID: 1, Name: "reader", Scopes: []string{apikey.ScopeProductsRead}},
+		"writer": {KeyID: 2, Name: "writer", Scopes: []string{apikey.ScopeProductsRead, apikey.ScopeProductsWrite}},
+		"admin":  {KeyID: 3, Name: "admin", Scopes: []string{apikey.ScopeAdmin}},
+	}
+	subject := func(c *gin.Context) {
+		c.String(http.StatusOK, ratelimit.Subject(c.Request.Context()))
+	}
+
+	r := gin.New()
+	r.Use(middleware.APIKeyAuth(keys), middleware.RequireAuthenticated(anonymous))
+	products := r.Group("/products",
+		middleware.RequireScopes(apikey.ScopeProductsRead, apikey.ScopeProductsWrite))
+	products.GET("/", subject)
+	products.POST("/", subject)
+	r.GET("/api-keys", middleware.RequireScope(apikey.ScopeAdmin), subject)
+	return r
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	r := newAPIKeyEngine(false)
+
+	cases := []struct {
+		name    string
+		method  string
+		path    string
+		key     string
+		status  int
+		subject string
+	}{
+		{"No key is rejected", http.MethodPost, "/products/", "", http.StatusUnauthorized, ""},
+		{"Unknown key", http.MethodGet, "/products/", "unknown", http.StatusUnauthorized, ""},
+		{"Lookup failure", http.MethodGet, "/products/", "broken", http.StatusInternalServerError, ""},
+		{"Read scope reads", http.MethodGet, "/products/", "reader", http.StatusOK, "apikey:1"},
+		{"Read scope cannot write", http.MethodPost, "/products/", "reader", http.StatusForbidden, ""},
+		{"Write scope writes", http.MethodPost, "/products/", "writer", http.StatusOK, "apikey:2"},
+		{"Product keys cannot manage keys", http.MethodGet, "/api-keys", "writer", http.StatusForbidden, ""},
+		{"Admin scope manages keys", http.MethodGet, "/api-keys", "admin", http.StatusOK, "apikey:3"},
+		{"Key management without a key", http.MethodGet, "/api-keys", "", http.StatusUnauthorized, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.key != "" {
+				req.Header.Set(middleware.APIKeyHeader, tc.key)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("Expected status %d, got %d: %s", tc.status, rec.Code, rec.Body.String())
+			}
+			if tc.status == http.StatusOK && rec.Body.String() != tc.subject {
+				t.Errorf("Expected rate limit subject %q, got %q", tc.subject, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticationWithoutAPIKey(t *testing.T) {
+	t.Run("Client certificate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api-keys", nil)
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+		rec := httptest.NewRecorder()
+		newAPIKeyEngine(false).ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected a verified client certificate to authenticate, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Unverified client certificate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api-keys", nil)
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+		rec := httptest.NewRecorder()
+		newAPIKeyEngine(false).ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected an unverified certificate to be rejected, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Anonymous allowed", func(t *testing.T) {
+		r := newAPIKeyEngine(true)
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodPost, "/products/", nil),
+			httptest.NewRequest(http.MethodGet, "/api-keys", nil),
+		} {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected anonymous %s %s to pass, got %d", req.Method, req.URL.Path, rec.Code)
+			}
+		}
+	})
+}
+
+func TestAnonymousAdminDefaults(t *testing.T) {
+	t.Setenv("APP_HTTP_PORT", "4000")
+	t.Setenv("APP_HTTP_ADDRESS", "127.0.0.1")
+	t.Setenv("APP_DATABASE_DSN", "postgresql://user:pass@db:5432/database?sslmode=disable")
+	t.Setenv("APP_REDIS_DSN", "redis:6379")
+	t.Setenv("APP_REDIS_PREFIX", "go-graphql")
+	t.Setenv("APP_REDIS_DEFAULT_TTL", "5")
+
+	for _, tc := range []struct {
+		env       string
+		anonymous string
+		want      bool
+	}{
+		{env: "development", want: true},
+		{env: "development", anonymous: "false", want: false},
+		{env: "test", want: false},
+		{env: "production", want: false},
+	} {
+		t.Setenv("APP_ENV", tc.env)
+		t.Setenv("APP_HTTP_ADMIN_ANONYMOUS", tc.anonymous)
+		cfg, err := config.NewConfig()
+		if err != nil {
+			t.Fatalf("Failed to load the %s config: %v", tc.env, err)
+		}
+		if cfg.HTTP.AdminAnonymous != tc.want {
+			t.Errorf("Expected AdminAnonymous %t in %s with %q, got %t", tc.want, tc.env, tc.anonymous, cfg.HTTP.AdminAnonymous)
+		}
+	}
+
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("APP_HTTP_ADMIN_ANONYMOUS", "true")
+	if _, err := config.NewConfig(); err == nil || !strings.Contains(err.Error(), "HTTP_ADMIN_ANONYMOUS") {
+		t.Errorf("Expected production to refuse anonymous admin calls, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-graphql/internal/config"
+	"go-graphql/internal/product/dto"
+	"go-graphql/internal/storage/cache"
+	"go-graphql/internal/storage/sql/sqlc"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestCacheStore(t *testing.T, mr *miniredis.Miniredis, redisCfg config.RedisCfg) *cache.Store {
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	redisCfg.Prefix = "go-graphql-test"
+	store, err := cache.NewCacheStore(client, &config.Config{Redis: redisCfg})
+	if err != nil {
+		t.Fatalf("Failed to create cache store: %v", err)
+	}
+	return store
+}
+
+func TestCacheStoreCodecs(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	want := dto.ProductResponse{ID: 7, Name: "Test Product", Description: "This is a test product", Price: 1000}
+
+	for _, codec := range []string{"json", "msgpack"} {
+		t.Run("Round trip "+codec, func(t *testing.T) {
+			store := newTestCacheStore(t, mr, config.RedisCfg{Codec: codec, Version: 1})
+			if err := store.Set(ctx, store.KeyProduct(want.ID), want, 1); err != nil {
+				t.Fatalf("Failed to set value: %v", err)
+			}
+			var got dto.ProductResponse
+			if err := store.Get(ctx, store.KeyProduct(want.ID), &got); err != nil {
+				t.Fatalf("Failed to get value: %v", err)
+			}
+			if got != want {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestCacheStoreCompressesLargeValues(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	store := newTestCacheStore(t, mr, config.RedisCfg{Codec: "json", CompressThreshold: 64})
+
+	want := dto.ClientListProductsResponse{}
+	for i := int32(1); i <= 100; i++ {
+		want = append(want, dto.ProductResponse{ID: i, Name: strings.Repeat("product", 5), Price: 1000})
+	}
+	if err := store.Set(ctx, store.KeyAllProducts(), want, 1); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+	raw, _ := mr.Get(store.KeyAllProducts())
+	if strings.Contains(raw, "product") {
+		t.Errorf("Expected compressed payload, got plain text")
+	}
+
+	var got dto.ClientListProductsResponse
+	if err := store.Get(ctx, store.KeyAllProducts(), &got); err != nil {
+		t.Fatalf("Failed to get value: %v", err)
+	}
+	if len(got) != len(want) || got[99] != want[99] {
+		t.Errorf("Decompressed list does not match the stored one")
+	}
+}
+
+func TestCacheStoreEvictsStaleEntries(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	store := newTestCacheStore(t, mr, config.RedisCfg{Codec: "msgpack", Version: 1})
+	key := store.KeyProduct(1)
+
+	t.Run("Version mismatch", func(t *testing.T) {
+		if err := store.Set(ctx, key, dto.ProductResponse{ID: 1}, 1); err != nil {
+			t.Fatalf("Failed to set value: %v", err)
+		}
+		bumped := newTestCacheStore(t, mr, config.RedisCfg{Codec: "msgpack", Version: 2})
+		var got dto.ProductResponse
+		if err := bumped.Get(ctx, key, &got); !errors.Is(err, cache.ErrStaleEntry) {
+			t.Fatalf("Expected ErrStaleEntry, got %v", err)
+		}
+		if mr.Exists(key) {
+			t.Errorf("Expected stale key to be evicted")
+		}
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		if err := store.Set(ctx, key, dto.ProductResponse{ID: 1}, 1); err != nil {
+			t.Fatalf("Failed to set value: %v", err)
+		}
+		var got sqlc.Product
+		if err := store.Get(ctx, key, &got); !errors.Is(err, cache.ErrStaleEntry) {
+			t.Fatalf("Expected ErrStaleEntry, got %v", err)
+		}
+	})
+
+	t.Run("Legacy blob", func(t *testing.T) {
+		mr.Set(key, `{"id":1}`)
+		var got dto.ProductResponse
+		if err := store.Get(ctx, key, &got); !errors.Is(err, cache.ErrStaleEntry) {
+			t.Fatalf("Expected ErrStaleEntry, got %v", err)
+		}
+		if mr.Exists(key) {
+			t.Errorf("Expected legacy key to be evicted")
+		}
+	})
+}
+
+func TestCacheStoreAdminOperations(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	store := newTestCacheStore(t, mr, config.RedisCfg{Codec: "msgpack", CompressThreshold: 1})
+	mr.Set("other-app:key", "untouched")
+
+	product := dto.ProductResponse{ID: 1, Name: "Test Product", Price: 1000}
+	if err := store.Set(ctx, store.KeyProduct(product.ID), product, 1); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+	if err := store.Set(ctx, store.KeyAllProducts(), []dto.ProductResponse{product}, 1); err != nil {
+		t.Fatalf("Failed to set value: %v", err)
+	}
+
+	t.Run("Inspect", func(t *testing.T) {
+		entry, found, err := store.Inspect(ctx, "product:1")
+		if err != nil || !found {
+			t.Fatalf("Expected key to be found, got found=%t err=%v", found, err)
+		}
+		if entry.Codec != "msgpack" || !entry.Compressed || entry.TTLSeconds <= 0 {
+			t.Errorf("Unexpected entry metadata: %+v", entry)
+		}
+		value, ok := entry.Value.(map[string]interface{})
+		if !ok || value["Name"] != product.Name {
+			t.Errorf("Unexpected decoded value: %#v", entry.Value)
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		var got dto.ProductResponse
+		store.Get(ctx, store.KeyProduct(product.ID), &got)
+		store.Get(ctx, store.KeyProduct(404), &got)
+		stats, err := store.Stats(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get stats: %v", err)
+		}
+		if stats.Keys != 2 || stats.Hits != 1 || stats.Misses != 1 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Delete all", func(t *testing.T) {
+		deleted, err := store.DeleteAll(ctx)
+		if err != nil {
+			t.Fatalf("Failed to purge cache: %v", err)
+		}
+		if deleted != 2 {
+			t.Errorf("Expected 2 deleted keys, got %d", deleted)
+		}
+		if !mr.Exists("other-app:key") {
+			t.Errorf("Expected keys outside the prefix to be kept")
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"compress/gzip"
+	"go-graphql/internal/config"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/http/response"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const catalogETag = `"products-v1"`
+
+var catalog = strings.Repeat(`{"name":"Tea","price":100},`, 20)
+
+func newCompressionEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Compress(config.CompressionCfg{Enabled: true, MinSize: 256}))
+	products := r.Group("/products",
+		middleware.Timeout(5*time.Second),
+		middleware.CacheControl(map[string]string{
+			"/products/":    "public, max-age=60",
+			"/products/:id": "public, max-age=30",
+		}))
+	products.GET("/", func(c *gin.Context) {
+		if response.NotModified(c, catalogETag) {
+			return
+		}
+		c.Data(http.StatusOK, "application/json", []byte(catalog))
+	})
+	products.GET("/:id", func(c *gin.Context) {
+		if c.Param("id") == "0" {
+			response.JSONError(c, http.StatusInternalServerError, response.ErrNotFound)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"name": "Tea"})
+	})
+	return r
+}
+
+func getWith(r *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCompression(t *testing.T) {
+	r := newCompressionEngine()
+
+	cases := []struct {
+		name     string
+		accept   string
+		encoding string
+	}{
+		{"Brotli preferred on ties", "gzip, br", "br"},
+		{"Highest q-value wins", "br;q=0.5, gzip", "gzip"},
+		{"Wildcard", "*", "br"},
+		{"Identity only", "identity", ""},
+		{"Refused codings", "br;q=0, gzip;q=0", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := getWith(r, "/products/", map[string]string{"Accept-Encoding": tc.accept})
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tc.encoding {
+				t.Fatalf("Expected Content-Encoding %q, got %q", tc.encoding, got)
+			}
+			if got := rec.Header().Get("Vary"); !strings.Contains(got, "Accept-Encoding") {
+				t.Errorf("Expected Vary to list Accept-Encoding, got %q", got)
+			}
+			if want := response.EncodedETag(catalogETag, tc.encoding); rec.Header().Get("ETag") != want {
+				t.Errorf("Expected ETag %s, got %s", want, rec.Header().Get("ETag"))
+			}
+
+			var body io.Reader = rec.Body
+			switch tc.encoding {
+			case "br":
+				body = brotli.NewReader(rec.Body)
+			case "gzip":
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gz
+			}
+			data, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != catalog {
+				t.Errorf("Expected the decoded body to match the catalog, got %d bytes", len(data))
+			}
+		})
+	}
+
+	t.Run("Small bodies are not compressed", func(t *testing.T) {
+		rec := getWith(r, "/products/1", map[string]string{"Accept-Encoding": "gzip"})
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no Content-Encoding, got %q", got)
+		}
+		if rec.Body.String() != `{"name":"Tea"}` {
+			t.Errorf("Unexpected body %s", rec.Body.String())
+		}
+	})
+}
+
+func TestConditionalGet(t *testing.T) {
+	r := newCompressionEngine()
+
+	t.Run("Matching ETag", func(t *testing.T) {
+		for _, tc := range []struct{ ifNoneMatch, accept string }{
+			{catalogETag, ""},
+			{`"products-v1-gzip"`, "gzip"},
+			{`W/"products-v1", "other"`, ""},
+			{"*", ""},
+		} {
+			rec := getWith(r, "/products/", map[string]string{"If-None-Match": tc.ifNoneMatch, "Accept-Encoding": tc.accept})
+			if rec.Code != http.StatusNotModified {
+				t.Fatalf("If-None-Match %s: expected status 304, got %d", tc.ifNoneMatch, rec.Code)
+			}
+			if rec.Body.Len() != 0 {
+				t.Errorf("If-None-Match %s: expected no body, got %q", tc.ifNoneMatch, rec.Body.String())
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "public, max-age=60" {
+				t.Errorf("If-None-Match %s: expected the route Cache-Control, got %q", tc.ifNoneMatch, got)
+			}
+			if want := response.EncodedETag(catalogETag, tc.accept); rec.Header().Get("ETag") != want {
+				t.Errorf("If-None-Match %s: expected ETag %s, got %s", tc.ifNoneMatch, want, rec.Header().Get("ETag"))
+			}
+		}
+	})
+
+	t.Run("Stale ETag", func(t *testing.T) {
+		rec := getWith(r, "/products/", map[string]string{"If-None-Match": `"products-v0"`})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Cache-Control per route", func(t *testing.T) {
+		if got := getWith(r, "/products/", nil).Header().Get("Cache-Control"); got != "public, max-age=60" {
+			t.Errorf("Expected list Cache-Control, got %q", got)
+		}
+		if got := getWith(r, "/products/1", nil).Header().Get("Cache-Control"); got != "public, max-age=30" {
+			t.Errorf("Expected product Cache-Control, got %q", got)
+		}
+		if got := getWith(r, "/products/0", nil).Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("Expected errors to be no-store, got %q", got)
+		}
+	})
+}
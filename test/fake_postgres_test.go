@@ -0,0 +1,329 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// fakeReply answers one statement received by a fakePostgres
+type fakeReply struct {
+	Code  string // SQLSTATE the statement fails with, when set
+	Type  uint32 // OID of the single column of the single row returned, 0 returns no row
+	Value string // text format value of the row
+	Tag   string // command tag, defaults to the first word of the statement
+	// Params are the OIDs of the statement parameters, bigint by default
+	Params []uint32
+	// Columns are the OIDs of the columns of Rows, whose Go values are
+	// encoded by pgtype. They replace Type and Value
+	Columns []uint32
+	Rows    [][]any
+}
+
+// columns returns the OIDs of the columns the reply describes
+func (r fakeReply) columns() []uint32 {
+	if r.Columns != nil {
+		return r.Columns
+	}
+	if r.Type != 0 {
+		return []uint32{r.Type}
+	}
+	return nil
+}
+
+// fakePostgres speaks just enough of the Postgres wire protocol for pgx to
+// connect, ping, run transactions and query rows, without a
+// database. It records the statements it executes.
+type fakePostgres struct {
+	ln     net.Listener
+	name   string
+	handle func(query string) fakeReply
+
+	mu         sync.Mutex
+	statements []string
+}
+
+// newFakePostgres starts a server answering statements with handle, a nil
+// handle answers SELECTs with the server name and succeeds otherwise.
+// handle may be called more than once per statement
+func newFakePostgres(t *testing.T, name string, handle func(query string) fakeReply) *fakePostgres {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	f := &fakePostgres{ln: ln, name: name, handle: handle}
+	if f.handle == nil {
+		f.handle = f.answerName
+	}
+	go f.serve()
+	t.Cleanup(func() { ln.Close() })
+	return f
+}
+
+func (f *fakePostgres) DSN() string {
+	return fmt.Sprintf("postgres://user:pass@%s/%s?sslmode=disable", f.ln.Addr(), f.name)
+}
+
+// Statements returns the statements executed so far, lowercased
+func (f *fakePostgres) Statements() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.statements...)
+}
+
+func (f *fakePostgres) answerName(query string) fakeReply {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(query)), "select") {
+		return fakeReply{Type: pgtype.TextOID, Value: f.name}
+	}
+	return fakeReply{}
+}
+
+func (f *fakePostgres) record(query string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statements = append(f.statements, strings.ToLower(strings.TrimSpace(query)))
+}
+
+func (f *fakePostgres) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			_ = f.session(pgproto3.NewBackend(conn, conn))
+		}()
+	}
+}
+
+var placeholder = regexp.MustCompile(`\$\d+`)
+
+// session serves one connection until the client terminates it
+func (f *fakePostgres) session(b *pgproto3.Backend) error {
+	if _, err := b.ReceiveStartupMessage(); err != nil {
+		return err
+	}
+	b.Send(&pgproto3.AuthenticationOk{})
+	for name, value := range map[string]string{
+		"server_version":              "16.0",
+		"client_encoding":             "UTF8",
+		"standard_conforming_strings": "on",
+		"DateStyle":                   "ISO, MDY",
+		"integer_datetimes":           "on",
+	} {
+		b.Send(&pgproto3.ParameterStatus{Name: name, Value: value})
+	}
+	b.Send(&pgproto3.BackendKeyData{ProcessID: 1, SecretKey: 1})
+
+	txStatus := byte('I')
+	b.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+	if err := b.Flush(); err != nil {
+		return err
+	}
+
+	statements := map[string]string{}
+	var portal string
+	var formats []int16 // result formats of the portal
+	failed := false     // an extended query failed, messages are skipped until Sync
+	for {
+		msg, err := b.Receive()
+		if err != nil {
+			return err
+		}
+		if _, ok := msg.(*pgproto3.Sync); !ok && failed {
+			continue
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.Terminate:
+			return nil
+		case *pgproto3.Query:
+			f.record(msg.String)
+			reply := f.handle(msg.String)
+			switch lower := strings.ToLower(strings.TrimSpace(msg.String)); {
+			case strings.HasPrefix(lower, "--") || lower == "":
+				b.Send(&pgproto3.EmptyQueryResponse{})
+			case reply.Code != "":
+				b.Send(errorResponse(reply))
+				txStatus = transactionStatus(txStatus, lower, true)
+			case reply.columns() != nil:
+				// simple queries return text rows
+				if err := sendRows(b, reply, nil, true); err != nil {
+					return err
+				}
+			default:
+				b.Send(&pgproto3.CommandComplete{CommandTag: []byte(commandTag(msg.String, reply))})
+				txStatus = transactionStatus(txStatus, lower, false)
+			}
+			b.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+		case *pgproto3.Parse:
+			statements[msg.Name] = msg.Query
+			b.Send(&pgproto3.ParseComplete{})
+		case *pgproto3.Describe:
+			query := statements[msg.Name]
+			reply := f.handle(query)
+			if msg.ObjectType == 'P' {
+				query = portal
+				reply = f.handle(query)
+			} else {
+				oids := reply.Params
+				if oids == nil {
+					oids = make([]uint32, len(placeholder.FindAllString(query, -1)))
+					for i := range oids {
+						oids[i] = pgtype.Int8OID
+					}
+				}
+				b.Send(&pgproto3.ParameterDescription{ParameterOIDs: oids})
+			}
+			if columns := reply.columns(); columns != nil {
+				fields := make([]pgproto3.FieldDescription, len(columns))
+				for i, oid := range columns {
+					fields[i] = pgproto3.FieldDescription{
+						Name: []byte(fmt.Sprintf("column%d", i+1)), DataTypeOID: oid, DataTypeSize: -1, TypeModifier: -1,
+					}
+					// a portal is described with the formats it was bound with
+					if msg.ObjectType == 'P' {
+						fields[i].Format = resultFormat(formats, i)
+					}
+				}
+				b.Send(&pgproto3.RowDescription{Fields: fields})
+			} else {
+				b.Send(&pgproto3.NoData{})
+			}
+		case *pgproto3.Bind:
+			portal = statements[msg.PreparedStatement]
+			formats = msg.ResultFormatCodes
+			b.Send(&pgproto3.BindComplete{})
+		case *pgproto3.Execute:
+			f.record(portal)
+			reply := f.handle(portal)
+			switch {
+			case reply.Code != "":
+				b.Send(errorResponse(reply))
+				failed = true
+			case reply.columns() != nil:
+				if err := sendRows(b, reply, formats, false); err != nil {
+					return err
+				}
+			default:
+				b.Send(&pgproto3.CommandComplete{CommandTag: []byte(commandTag(portal, reply))})
+			}
+		case *pgproto3.Close:
+			b.Send(&pgproto3.CloseComplete{})
+		case *pgproto3.Sync:
+			failed = false
+			b.Send(&pgproto3.ReadyForQuery{TxStatus: txStatus})
+		case *pgproto3.Flush:
+		default:
+			return errors.New("fake postgres: unsupported message")
+		}
+		if err := b.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// sendRows sends the rows of reply in formats, a simple query also needs
+// their RowDescription
+func sendRows(b *pgproto3.Backend, reply fakeReply, formats []int16, describe bool) error {
+	if describe {
+		fields := make([]pgproto3.FieldDescription, len(reply.columns()))
+		for i, oid := range reply.columns() {
+			fields[i] = pgproto3.FieldDescription{
+				Name: []byte(fmt.Sprintf("column%d", i+1)), DataTypeOID: oid, DataTypeSize: -1, TypeModifier: -1,
+			}
+		}
+		b.Send(&pgproto3.RowDescription{Fields: fields})
+	}
+	if reply.Columns == nil {
+		binary := resultFormat(formats, 0) == pgproto3.BinaryFormat
+		b.Send(&pgproto3.DataRow{Values: [][]byte{encodeValue(reply, binary)}})
+		b.Send(&pgproto3.CommandComplete{CommandTag: []byte("SELECT 1")})
+		return nil
+	}
+	for _, row := range reply.Rows {
+		values, err := encodeRow(reply.Columns, formats, row)
+		if err != nil {
+			return err
+		}
+		b.Send(&pgproto3.DataRow{Values: values})
+	}
+	b.Send(&pgproto3.CommandComplete{CommandTag: []byte(fmt.Sprintf("SELECT %d", len(reply.Rows)))})
+	return nil
+}
+
+func errorResponse(reply fakeReply) *pgproto3.ErrorResponse {
+	return &pgproto3.ErrorResponse{Severity: "ERROR", Code: reply.Code, Message: "fake error " + reply.Code}
+}
+
+func commandTag(query string, reply fakeReply) string {
+	if reply.Tag != "" {
+		return reply.Tag
+	}
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// transactionStatus follows the statements opening and closing transactions
+func transactionStatus(status byte, statement string, failed bool) byte {
+	switch {
+	case strings.HasPrefix(statement, "begin"):
+		if failed {
+			return status
+		}
+		return 'T'
+	case statement == "commit" || statement == "rollback":
+		return 'I'
+	case strings.HasPrefix(statement, "rollback to savepoint") && !failed:
+		return 'T'
+	case failed && status != 'I':
+		return 'E'
+	}
+	return status
+}
+
+// resultFormat returns the format of column i, Bind sends none for text,
+// one for every column or one per column
+func resultFormat(formats []int16, i int) int16 {
+	switch len(formats) {
+	case 0:
+		return pgproto3.TextFormat
+	case 1:
+		return formats[0]
+	}
+	return formats[i]
+}
+
+func encodeRow(columns []uint32, formats []int16, row []any) ([][]byte, error) {
+	types := pgtype.NewMap()
+	values := make([][]byte, len(row))
+	for i, value := range row {
+		encoded, err := types.Encode(columns[i], resultFormat(formats, i), value, nil)
+		if err != nil {
+			return nil, fmt.Errorf("fake postgres: encode column %d: %w", i+1, err)
+		}
+		values[i] = encoded
+	}
+	return values, nil
+}
+
+func encodeValue(reply fakeReply, binary bool) []byte {
+	if binary && reply.Type == pgtype.BoolOID {
+		if reply.Value == "t" || reply.Value == "true" {
+			return []byte{1}
+		}
+		return []byte{0}
+	}
+	return []byte(reply.Value)
+}
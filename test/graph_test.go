@@ -26,7 +26,8 @@ func TestProductsGraphQLFilters(t *testing.T) {
 
 		// Run filter tests
 		queryProductsByFilter(t, addr, fmt.Sprintf(`id: %d`, product.ID), func(p map[string]interface{}) bool {
-			return int32(p["id"].(float64)) == product.ID
+			// the product is created active
+			return int32(p["id"].(float64)) == product.ID && p["isActive"] == true
 		})
 
 		queryProductsByFilter(t, addr, fmt.Sprintf(`name: \"%s\"`, product.Name), func(p map[string]interface{}) bool {
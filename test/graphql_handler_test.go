@@ -0,0 +1,63 @@
+package test
+
+import (
+	"go-graphql/internal/config"
+	"go-graphql/internal/graph/resolvers"
+	"go-graphql/internal/metrics"
+	"go-graphql/internal/server"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func newTestGraphQLHandler(t *testing.T, cfg *config.Config) http.Handler {
+	mr := miniredis.RunT(t)
+	m := metrics.New(newTestRouter(t), newTestCacheStore(t, mr, config.RedisCfg{}))
+	limiter := newTestLimiter(t, mr, config.RateLimitCfg{})
+	return server.NewGraphQLHandler(cfg, &resolvers.Resolver{}, m, noop.NewTracerProvider(), limiter, nil, nil)
+}
+
+func TestGraphQLHandlerIntrospection(t *testing.T) {
+	const query = `{"query":"{ __schema { queryType { name } } }"}`
+	for _, tc := range []struct {
+		env  string
+		want string
+	}{
+		{env: "development", want: `"queryType":{"name":"Query"}`},
+		{env: "production", want: `introspection disabled`},
+	} {
+		cfg := &config.Config{ENV: tc.env, GraphQL: config.GraphQLCfg{Transports: []string{config.GraphQLTransportPOST}}}
+		h := newTestGraphQLHandler(t, cfg)
+
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(query))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if !strings.Contains(rec.Body.String(), tc.want) {
+			t.Errorf("%s: expected response to contain %q, got %s", tc.env, tc.want, rec.Body.String())
+		}
+	}
+}
+
+func TestGraphQLHandlerTransports(t *testing.T) {
+	cfg := &config.Config{GraphQL: config.GraphQLCfg{Transports: []string{config.GraphQLTransportPOST}}}
+	h := newTestGraphQLHandler(t, cfg)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/query?query=%7B__typename%7D", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected GET to be rejected when only POST is enabled, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query":"{ __typename }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"__typename":"Query"`) {
+		t.Errorf("Expected POST to run the query, got %d %s", rec.Code, rec.Body.String())
+	}
+}
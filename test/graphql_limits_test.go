@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-graphql/internal/config"
+	"go-graphql/internal/graph/generated"
+	"go-graphql/internal/graph/limits"
+	"go-graphql/internal/graph/model"
+	"go-graphql/internal/graph/resolvers"
+	product "go-graphql/internal/product/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"go.uber.org/zap"
+)
+
+func TestGraphQLLimits(t *testing.T) {
+	schema := generated.NewExecutableSchema(generated.Config{
+		Resolvers:  &resolvers.Resolver{},
+		Complexity: limits.Complexity(100),
+	})
+	srv := handler.New(schema)
+	srv.AddTransport(transport.POST{})
+	srv.Use(limits.NewDepthLimit(2))
+	srv.Use(extension.FixedComplexityLimit(50))
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{
+			name:  "Too deep",
+			query: `{ products { products { id } } }`,
+			code:  limits.CodeDepthLimit,
+		},
+		{
+			name:  "Too deep through a fragment",
+			query: `{ products { ...Page } } fragment Page on ProductConnection { products { id } }`,
+			code:  limits.CodeDepthLimit,
+		},
+		{
+			name:  "Too complex, each item costs its selection",
+			query: `{ products(pagination: { limit: 30 }) { total } a: products(pagination: { limit: 30 }) { total } }`,
+			code:  "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			name:  "Limits above the maximum are priced at the maximum",
+			query: `{ products(pagination: { limit: 100000 }) { total } }`,
+			code:  "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"query": tt.query})
+			req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			var resp struct {
+				Errors []struct {
+					Message    string         `json:"message"`
+					Extensions map[string]any `json:"extensions"`
+				} `json:"errors"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Errors) != 1 {
+				t.Fatalf("Expected one error, got %s", rec.Body.String())
+			}
+			if code := resp.Errors[0].Extensions["code"]; code != tt.code {
+				t.Errorf("Expected code %s, got %v (%s)", tt.code, code, resp.Errors[0].Message)
+			}
+		})
+	}
+}
+
+func TestListProductsMaxLimit(t *testing.T) {
+	cfg := &config.Config{GraphQL: config.GraphQLCfg{MaxLimit: 100}}
+	svc := product.New(nil, nil, zap.NewNop(), nil, cfg)
+
+	limit, offset := 101, -1
+	for _, pagination := range []*model.PaginationInput{
+		{Limit: &limit},
+		{Offset: &offset},
+	} {
+		_, err := svc.ListProducts(context.Background(), nil, pagination)
+		if !errors.Is(err, product.ErrInvalidPagination) {
+			t.Errorf("Expected ErrInvalidPagination, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	apikey "go-graphql/internal/apikey/service"
+	"go-graphql/internal/config"
+	"go-graphql/internal/health"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/server"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T, dir string) *testCA {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", der)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// issue writes a certificate signed by the CA and its key as name.pem and
+// name-key.pem
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64, usage x509.ExtKeyUsage) tls.Certificate {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to issue certificate: %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	writePEM(t, filepath.Join(dir, name+"-key.pem"), "EC PRIVATE KEY", keyDER)
+	writePEM(t, filepath.Join(dir, name+".pem"), "CERTIFICATE", der)
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, name+".pem"), filepath.Join(dir, name+"-key.pem"))
+	if err != nil {
+		t.Fatalf("Failed to load certificate: %v", err)
+	}
+	return pair
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestHTTPServerTLS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+	ca.issue(t, dir, "server", 10, x509.ExtKeyUsageServerAuth)
+	clientCert := ca.issue(t, dir, "client", 20, x509.ExtKeyUsageClientAuth)
+
+	cfg := &config.Config{
+		HTTPAddress: "127.0.0.1",
+		HTTPPort:    freePort(t),
+		HTTP: config.HTTPCfg{TLS: config.HTTPTLSCfg{
+			Enabled:        true,
+			CertFile:       filepath.Join(dir, "server.pem"),
+			KeyFile:        filepath.Join(dir, "server-key.pem"),
+			ClientCAFile:   filepath.Join(dir, "ca.pem"),
+			ReloadInterval: 1,
+		}},
+	}
+	engine := gin.New()
+	engine.GET("/public", func(c *gin.Context) { c.String(http.StatusOK, c.Request.Proto) })
+	keys := fakeKeys{"admin": {KeyID: 1, Name: "admin", Scopes: []string{apikey.ScopeAdmin}}}
+	engine.GET("/admin", middleware.APIKeyAuth(keys), middleware.RequireAuthenticated(false),
+		func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	hs, err := server.NewHTTPServer(engine, cfg, zap.NewNop(), health.New(nil, nil, nil, nil))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	lc := fxtest.NewLifecycle(t)
+	server.StartHTTPServer(lc, hs)
+	lc.RequireStart()
+	defer lc.RequireStop()
+	time.Sleep(100 * time.Millisecond)
+
+	base := fmt.Sprintf("https://127.0.0.1:%d", cfg.HTTPPort)
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: ca.pool, Certificates: certs},
+			ForceAttemptHTTP2: true,
+		}}
+	}
+
+	resp, err := newClient().Get(base + "/public")
+	if err != nil {
+		t.Fatalf("Failed to call public route: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ProtoMajor != 2 {
+		t.Errorf("Expected 200 over HTTP/2, got %d over %s", resp.StatusCode, resp.Proto)
+	}
+
+	resp, err = newClient().Get(base + "/admin")
+	if err != nil {
+		t.Fatalf("Failed to call admin route: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a client certificate nor an API key, got %d", resp.StatusCode)
+	}
+
+	resp, err = newClient(clientCert).Get(base + "/admin")
+	if err != nil {
+		t.Fatalf("Failed to call admin route: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with a client certificate, got %d", resp.StatusCode)
+	}
+
+	// an API key is enough without a client certificate
+	req, _ := http.NewRequest(http.MethodGet, base+"/admin", nil)
+	req.Header.Set(middleware.APIKeyHeader, "admin")
+	resp, err = newClient().Do(req)
+	if err != nil {
+		t.Fatalf("Failed to call admin route: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with an API key, got %d", resp.StatusCode)
+	}
+
+	// rotate the server certificate, the next handshake must present it
+	ca.issue(t, dir, "server", 11, x509.ExtKeyUsageServerAuth)
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(dir, "server.pem"), future, future)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := newClient().Get(base + "/public")
+		if err != nil {
+			t.Fatalf("Failed to call public route after rotation: %v", err)
+		}
+		resp.Body.Close()
+		if resp.TLS.PeerCertificates[0].SerialNumber.Int64() == 11 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the rotated certificate to be served")
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
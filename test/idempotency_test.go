@@ -0,0 +1,137 @@
+package test
+
+import (
+	"go-graphql/internal/config"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/idempotency"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newIdempotencyStore(t *testing.T, mr *miniredis.Miniredis) *idempotency.Store {
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	cfg := &config.Config{
+		Redis:       config.RedisCfg{Prefix: "go-graphql-test"},
+		Idempotency: config.IdempotencyCfg{Enabled: true, TTL: 60, LockTimeout: 30},
+	}
+	return idempotency.New(cfg, client, zap.NewNop())
+}
+
+func postWithKey(r *gin.Engine, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+
+	calls := 0
+	status := http.StatusCreated
+	r := gin.New()
+	r.Use(middleware.Idempotency(newIdempotencyStore(t, mr)))
+	r.POST("/products", func(c *gin.Context) {
+		calls++
+		c.JSON(status, gin.H{"id": calls})
+	})
+
+	first := postWithKey(r, "/products", "create-1", `{"name":"Tea","price":100}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", first.Code)
+	}
+
+	t.Run("Retry replays the first response", func(t *testing.T) {
+		rec := postWithKey(r, "/products", "create-1", `{ "name": "Tea", "price": 100 }`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d", rec.Code)
+		}
+		if rec.Body.String() != first.Body.String() {
+			t.Errorf("Expected body %s, got %s", first.Body.String(), rec.Body.String())
+		}
+		if got := rec.Header().Get("Content-Type"); got != first.Header().Get("Content-Type") {
+			t.Errorf("Expected Content-Type %q, got %q", first.Header().Get("Content-Type"), got)
+		}
+		if rec.Header().Get(middleware.IdempotentReplayedHeader) != "true" {
+			t.Errorf("Expected %s header on the replayed response", middleware.IdempotentReplayedHeader)
+		}
+		if calls != 1 {
+			t.Errorf("Expected the handler to run once, ran %d times", calls)
+		}
+	})
+
+	t.Run("Key reused with another body", func(t *testing.T) {
+		rec := postWithKey(r, "/products", "create-1", `{"name":"Coffee","price":100}`)
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("Expected status 409, got %d", rec.Code)
+		}
+	})
+
+	t.Run("Requests without a key run every time", func(t *testing.T) {
+		before := calls
+		postWithKey(r, "/products", "", `{"name":"Tea","price":100}`)
+		postWithKey(r, "/products", "", `{"name":"Tea","price":100}`)
+		if calls != before+2 {
+			t.Errorf("Expected the handler to run twice, ran %d times", calls-before)
+		}
+	})
+
+	t.Run("Server errors are not replayed", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		postWithKey(r, "/products", "create-2", `{"name":"Mate"}`)
+		status = http.StatusCreated
+
+		before := calls
+		rec := postWithKey(r, "/products", "create-2", `{"name":"Mate"}`)
+		if rec.Code != http.StatusCreated || calls != before+1 {
+			t.Errorf("Expected the retry to run and return 201, got %d", rec.Code)
+		}
+	})
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	r := gin.New()
+	r.Use(middleware.Idempotency(newIdempotencyStore(t, mr)))
+	r.POST("/products", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- postWithKey(r, "/products", "slow", `{"name":"Tea"}`) }()
+	<-started
+
+	rec := postWithKey(r, "/products", "slow", `{"name":"Tea"}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 while the first request runs, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After on the in-progress conflict")
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("Expected the first request to return 201, got %d", first.Code)
+	}
+	if rec := postWithKey(r, "/products", "slow", `{"name":"Tea"}`); rec.Code != http.StatusCreated {
+		t.Errorf("Expected the stored 201 once the first request completed, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"go-graphql/internal/config"
+	"go-graphql/internal/loglevel"
+	"go-graphql/internal/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLoggerPackageLevels(t *testing.T) {
+	cfg := &config.Config{Log: config.LogCfg{Level: "warn", Packages: "sql=debug"}}
+	levels, err := logger.NewLevels(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create levels: %v", err)
+	}
+	log, err := logger.NewLogger(cfg, levels)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	cases := []struct {
+		log   *zap.Logger
+		level zapcore.Level
+		want  bool
+	}{
+		{log, zapcore.InfoLevel, false},
+		{log, zapcore.WarnLevel, true},
+		{log.Named("sql"), zapcore.DebugLevel, true},
+		{log.Named("sql").Named("router"), zapcore.DebugLevel, true},
+		{log.Named("product"), zapcore.InfoLevel, false},
+	}
+	for _, tc := range cases {
+		if got := tc.log.Check(tc.level, "message") != nil; got != tc.want {
+			t.Errorf("Logger %q at %s: expected enabled=%t, got %t", tc.log.Name(), tc.level, tc.want, got)
+		}
+	}
+
+	if err := levels.SetLevel("", "debug"); err != nil {
+		t.Fatalf("Failed to change level: %v", err)
+	}
+	if log.Named("product").Check(zapcore.DebugLevel, "message") == nil {
+		t.Errorf("Expected debug to be enabled after changing the root level")
+	}
+}
+
+func TestLogLevelAdminEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	levels, err := logger.NewLevels(&config.Config{Log: config.LogCfg{Level: "info"}})
+	if err != nil {
+		t.Fatalf("Failed to create levels: %v", err)
+	}
+	r := gin.New()
+	loglevel.New(levels, zap.NewNop()).RegisterRoutes(r.Group("/api/v1/admin/log/level"))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/log/level",
+		strings.NewReader(`{"package":"sql","level":"debug"}`))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 OK, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var data loglevel.LevelResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &data); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if data.Level != "info" || data.Packages["sql"] != "debug" {
+		t.Errorf("Unexpected levels: %+v", data)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/admin/log/level", strings.NewReader(`{"level":"loud"}`))
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown level, got %d", rec.Code)
+	}
+}
+
+func TestLoggerLifecycleSetsGlobalLogger(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	lc := fxtest.NewLifecycle(t)
+	logger.RegisterLoggerLifecycle(lc, zap.New(core))
+	lc.RequireStart()
+
+	// outside of a request Ctx falls back to the application logger
+	logger.Ctx(context.Background()).Info("outside request")
+	if logs.FilterMessage("outside request").Len() != 1 {
+		t.Errorf("Expected the fallback logger to write to the application logger")
+	}
+
+	lc.RequireStop()
+	logger.Ctx(context.Background()).Info("after stop")
+	if logs.FilterMessage("after stop").Len() != 0 {
+		t.Errorf("Expected the previous global logger to be restored on stop")
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"go-graphql/internal/config"
+	"go-graphql/internal/graph/persisted"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/metrics"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter(t, newNamedPostgres(t, "replica-a").DSN(), newNamedPostgres(t, "replica-b").DSN())
+	store := newTestCacheStore(t, miniredis.RunT(t), config.RedisCfg{})
+	var missing struct{}
+	store.Get(context.Background(), store.KeyProduct(1), &missing)
+
+	m := metrics.New(router, store)
+	r := gin.New()
+	r.Use(middleware.Metrics(m))
+	r.GET("/products/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/7", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+	// clients choose the method, unknown ones must not grow the series
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/products/7", nil))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`go_graphql_http_requests_total{method="GET",route="/products/:id",status="200"} 1`,
+		`go_graphql_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`go_graphql_http_request_duration_seconds_count{method="GET",route="/products/:id"} 1`,
+		`go_graphql_cache_misses_total 1`,
+		`go_graphql_http_requests_total{method="other",route="unmatched",status="404"} 1`,
+		`go_graphql_db_pool_max_conns{pool="primary"}`,
+		`go_graphql_db_pool_max_conns{pool="replica_1"}`,
+		`go_graphql_db_pool_max_conns{pool="replica_2"}`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metrics to contain %q", want)
+		}
+	}
+	if strings.Contains(string(body), `method="BREW"`) {
+		t.Errorf("Expected non-standard methods to be recorded as other")
+	}
+}
+
+func TestGraphQLMetricsBoundOperationNames(t *testing.T) {
+	m := metrics.New(newTestRouter(t), newTestCacheStore(t, miniredis.RunT(t), config.RedisCfg{}))
+	manifest := persisted.Manifest{persisted.Hash(`query Trusted { __typename }`): `query Trusted { __typename }`}
+	srv := newPersistedServer(m.GraphQL(manifest.OperationNames()...))
+
+	// clients name operations freely, the names beyond the first 100 are not labels
+	for i := range 150 {
+		postPersisted(t, srv, fmt.Sprintf("query Op%d { __typename }", i), "")
+	}
+	postPersisted(t, srv, `query Trusted { __typename }`, "")
+	postPersisted(t, srv, typenameQuery, "")
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`go_graphql_graphql_operations_total{operation="Op99",result="success",type="query"} 1`,
+		`go_graphql_graphql_operations_total{operation="other",result="success",type="query"} 50`,
+		`go_graphql_graphql_operations_total{operation="Trusted",result="success",type="query"} 1`,
+		`go_graphql_graphql_operations_total{operation="anonymous",result="success",type="query"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %q", want)
+		}
+	}
+	if strings.Contains(body, `operation="Op100"`) {
+		t.Errorf("Expected operation names beyond the limit to be grouped as other")
+	}
+}
@@ -0,0 +1,384 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-graphql/internal/config"
+	"go-graphql/internal/storage/sql/migrate"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/joho/godotenv"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+// requireMigrationDatabase returns a config migrating a fresh schema of the
+// test database, dropped after the test. The test is skipped when the
+// database is unreachable
+func requireMigrationDatabase(t *testing.T) (*config.Config, *pgx.Conn) {
+	t.Helper()
+	dsn := os.Getenv("APP_DATABASE_DSN")
+	if dsn == "" {
+		env, err := godotenv.Read("../.env.test")
+		if err != nil {
+			t.Fatalf("Failed to read .env.test: %v", err)
+		}
+		dsn = env["APP_DATABASE_DSN"]
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Skipf("Postgres is not reachable at APP_DATABASE_DSN: %v", err)
+	}
+
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if _, err := conn.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		conn.Close(context.Background())
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		conn.Close(context.Background())
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("Failed to parse APP_DATABASE_DSN: %v", err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+	if _, err := conn.Exec(ctx, "SET search_path TO "+schema); err != nil {
+		t.Fatalf("Failed to set search_path: %v", err)
+	}
+	return &config.Config{Database: config.DatabaseCfg{DSN: u.String()}}, conn
+}
+
+func tableExists(t *testing.T, conn *pgx.Conn, table string) bool {
+	t.Helper()
+	var name *string
+	if err := conn.QueryRow(context.Background(), "SELECT to_regclass($1)::text", table).Scan(&name); err != nil {
+		t.Fatalf("Failed to look up %s: %v", table, err)
+	}
+	return name != nil
+}
+
+func TestLatestVersionMatchesMigrationFiles(t *testing.T) {
+	entries, err := os.ReadDir(filepath.Join("..", migrate.Dir))
+	if err != nil {
+		t.Fatalf("Failed to read migrations: %v", err)
+	}
+	name := regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+	var want uint64
+	for _, entry := range entries {
+		if match := name.FindStringSubmatch(entry.Name()); match != nil {
+			version, _ := strconv.ParseUint(match[1], 10, 64)
+			want = max(want, version)
+		}
+	}
+
+	got, err := migrate.LatestVersion()
+	if err != nil {
+		t.Fatalf("Failed to read latest version: %v", err)
+	}
+	if want == 0 || uint64(got) != want {
+		t.Errorf("Expected latest version %d, got %d", want, got)
+	}
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"0007_products.up.sql", "0007_products.down.sql", "0012_notes.txt", "embed.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	paths, err := migrate.Create(dir, "  Add Stock-Column! ")
+	if err != nil {
+		t.Fatalf("Failed to create migration: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "0008_add_stock_column.up.sql"),
+		filepath.Join(dir, "0008_add_stock_column.down.sql"),
+	}
+	if !slices.Equal(paths, want) {
+		t.Errorf("Expected %v, got %v", want, paths)
+	}
+	for _, path := range want {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to exist: %v", path, err)
+		}
+	}
+
+	if _, err := migrate.Create(dir, "!!!"); err == nil {
+		t.Errorf("Expected an error for a name without letters or digits")
+	}
+	if _, err := migrate.Create(filepath.Join(dir, "missing"), "name"); err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+}
+
+func TestCreateFirstMigration(t *testing.T) {
+	paths, err := migrate.Create(t.TempDir(), "products")
+	if err != nil {
+		t.Fatalf("Failed to create migration: %v", err)
+	}
+	if len(paths) != 2 || !strings.HasSuffix(paths[0], "0001_products.up.sql") || !strings.HasSuffix(paths[1], "0001_products.down.sql") {
+		t.Errorf("Expected the 0001 up/down pair, got %v", paths)
+	}
+}
+
+func TestGotoRejectsUnknownVersion(t *testing.T) {
+	latest, err := migrate.LatestVersion()
+	if err != nil {
+		t.Fatalf("Failed to read latest version: %v", err)
+	}
+	// rejected before connecting
+	runner := migrate.NewRunner(&config.Config{Database: config.DatabaseCfg{DSN: unreachableDSN}})
+	err = runner.Goto(latest + 1)
+	if err == nil || !strings.Contains(err.Error(), "up to the latest migration") {
+		t.Errorf("Expected a version out of range error, got %v", err)
+	}
+}
+
+func TestGotoMigratesUpAndDown(t *testing.T) {
+	cfg, conn := requireMigrationDatabase(t)
+	runner := migrate.NewRunner(cfg)
+	latest, err := migrate.LatestVersion()
+	if err != nil {
+		t.Fatalf("Failed to read latest version: %v", err)
+	}
+
+	for _, version := range []uint{1, latest, 1} {
+		if err := runner.Goto(version); err != nil {
+			t.Fatalf("Failed to go to version %d: %v", version, err)
+		}
+		status, err := runner.Status()
+		if err != nil {
+			t.Fatalf("Failed to read status: %v", err)
+		}
+		if !status.Applied || status.Version != version || status.Dirty {
+			t.Errorf("Expected clean version %d, got %+v", version, status)
+		}
+	}
+	if !tableExists(t, conn, "products") || tableExists(t, conn, "api_keys") {
+		t.Errorf("Expected only the tables of version 1")
+	}
+
+	if err := runner.Goto(0); err != nil {
+		t.Fatalf("Failed to go to version 0: %v", err)
+	}
+	status, err := runner.Status()
+	if err != nil {
+		t.Fatalf("Failed to read status: %v", err)
+	}
+	if status.Applied {
+		t.Errorf("Expected no migration applied after goto 0, got %+v", status)
+	}
+	if tableExists(t, conn, "products") {
+		t.Errorf("Expected goto 0 to drop the products table")
+	}
+	// going to the current version is not an error
+	if err := runner.Goto(0); err != nil {
+		t.Errorf("Expected goto 0 on an empty schema to succeed, got %v", err)
+	}
+}
+
+// lockedPostgres never grants the migration lock, as if another instance
+// were migrating
+func lockedPostgres(t *testing.T) *fakePostgres {
+	return newFakePostgres(t, "locked", func(query string) fakeReply {
+		if strings.Contains(query, "pg_try_advisory_lock") {
+			return fakeReply{Type: pgtype.BoolOID, Value: "f"}
+		}
+		return fakeReply{}
+	})
+}
+
+func TestUpTimesOutWaitingForLock(t *testing.T) {
+	db := lockedPostgres(t)
+	runner := migrate.NewRunner(&config.Config{Database: config.DatabaseCfg{DSN: db.DSN(), MigrateTimeout: 1}})
+
+	start := time.Now()
+	err := runner.Up(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "migration lock held by another instance") {
+		t.Fatalf("Expected a lock timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 3*time.Second {
+		t.Errorf("Expected to give up after the 1s migrate timeout, took %s", elapsed)
+	}
+	attempts := 0
+	for _, statement := range db.Statements() {
+		if strings.Contains(statement, "pg_try_advisory_lock") {
+			attempts++
+		}
+	}
+	if attempts < 2 {
+		t.Errorf("Expected the lock to be polled until the timeout, got %d attempts", attempts)
+	}
+}
+
+func TestUpReleasesLockOnFailure(t *testing.T) {
+	db := newFakePostgres(t, "failing", func(query string) fakeReply {
+		if strings.Contains(query, "advisory") {
+			return fakeReply{Type: pgtype.BoolOID, Value: "t"}
+		}
+		// every statement of the migrate driver fails
+		return fakeReply{Code: "XX000"}
+	})
+	runner := migrate.NewRunner(&config.Config{Database: config.DatabaseCfg{DSN: db.DSN(), MigrateTimeout: 5}})
+
+	if err := runner.Up(context.Background()); err == nil {
+		t.Fatalf("Expected the migration to fail")
+	}
+	statements := db.Statements()
+	if !slices.ContainsFunc(statements, func(s string) bool { return strings.Contains(s, "pg_advisory_unlock") }) {
+		t.Errorf("Expected the migration lock to be released, got %q", statements)
+	}
+}
+
+func TestMigrateOnStartReportsLockTimeout(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseCfg{DSN: lockedPostgres(t).DSN(), AutoMigrate: true, MigrateTimeout: 1}}
+	readiness := migrate.NewReadiness()
+	lc := fxtest.NewLifecycle(t)
+	migrate.RunMigrations(lc, migrate.NewRunner(cfg), readiness, cfg, zap.NewNop())
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ready, err := readiness.Ready()
+		if ready {
+			t.Fatalf("Expected the schema not to be ready")
+		}
+		if err != nil && strings.Contains(err.Error(), "migration lock") {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected readiness to report the lock timeout, got %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// migratingPostgres denies the migration lock until the instance holding
+// it is done, the schema is then at the latest version
+func migratingPostgres(t *testing.T, done *atomic.Bool) *fakePostgres {
+	latest, err := migrate.LatestVersion()
+	if err != nil {
+		t.Fatalf("Failed to read the latest version: %v", err)
+	}
+	return newFakePostgres(t, "migrating", func(query string) fakeReply {
+		switch {
+		case strings.Contains(query, "pg_try_advisory_lock"):
+			if done.Load() {
+				return fakeReply{Type: pgtype.BoolOID, Value: "t"}
+			}
+			return fakeReply{Type: pgtype.BoolOID, Value: "f"}
+		case strings.Contains(query, "CURRENT_SCHEMA"):
+			return fakeReply{Type: pgtype.TextOID, Value: "public"}
+		case strings.Contains(query, "information_schema.tables"):
+			return fakeReply{Columns: []uint32{pgtype.Int8OID}, Rows: [][]any{{1}}}
+		case strings.Contains(query, "SELECT version, dirty"):
+			return fakeReply{Columns: []uint32{pgtype.Int8OID, pgtype.BoolOID}, Rows: [][]any{{int64(latest), false}}}
+		}
+		return fakeReply{}
+	})
+}
+
+func TestMigrateOnStartRetriesUntilSchemaIsMigrated(t *testing.T) {
+	var done atomic.Bool
+	cfg := &config.Config{Database: config.DatabaseCfg{DSN: migratingPostgres(t, &done).DSN(), AutoMigrate: true, MigrateTimeout: 1}}
+	readiness := migrate.NewReadiness()
+	lc := fxtest.NewLifecycle(t)
+	migrate.RunMigrations(lc, migrate.NewRunner(cfg), readiness, cfg, zap.NewNop())
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	waitReadiness := func(timeout time.Duration, check func(ready bool, err error) bool) (bool, error) {
+		deadline := time.Now().Add(timeout)
+		for {
+			ready, err := readiness.Ready()
+			if check(ready, err) || time.Now().After(deadline) {
+				return ready, err
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	// the other instance still holds the lock
+	ready, err := waitReadiness(5*time.Second, func(_ bool, err error) bool {
+		return err != nil && strings.Contains(err.Error(), "migration lock")
+	})
+	if ready || err == nil || !strings.Contains(err.Error(), "migration lock") {
+		t.Fatalf("Expected readiness to report the lock timeout, got %v, %v", ready, err)
+	}
+
+	// it migrated the schema and released the lock
+	done.Store(true)
+	if ready, err := waitReadiness(15*time.Second, func(ready bool, _ error) bool { return ready }); !ready {
+		t.Fatalf("Expected the schema to become ready once migrated, got %v", err)
+	}
+}
+
+func TestUpRefusesDirtySchema(t *testing.T) {
+	cfg, conn := requireMigrationDatabase(t)
+	runner := migrate.NewRunner(cfg)
+	if err := runner.Goto(1); err != nil {
+		t.Fatalf("Failed to go to version 1: %v", err)
+	}
+	// a migration failed half way
+	if _, err := conn.Exec(context.Background(), "UPDATE schema_migrations SET dirty = true"); err != nil {
+		t.Fatalf("Failed to mark the schema dirty: %v", err)
+	}
+
+	var dirty *migrate.DirtyError
+	if err := runner.Up(context.Background()); !errors.As(err, &dirty) || dirty.Version != 1 {
+		t.Fatalf("Expected a DirtyError at version 1, got %v", err)
+	}
+	if tableExists(t, conn, "api_keys") {
+		t.Errorf("Expected no migration to run on a dirty schema")
+	}
+
+	readiness := migrate.NewReadiness()
+	lc := fxtest.NewLifecycle(t)
+	migrate.RunMigrations(lc, runner, readiness, &config.Config{Database: config.DatabaseCfg{AutoMigrate: true}}, zap.NewNop())
+	lc.RequireStart()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, err := readiness.Ready()
+		if errors.As(err, &dirty) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected readiness to report the dirty schema, got %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	lc.RequireStop()
+
+	// forcing the version clears the dirty flag once the schema was fixed
+	if err := runner.Force(1); err != nil {
+		t.Fatalf("Failed to force version 1: %v", err)
+	}
+	if err := runner.Up(context.Background()); err != nil {
+		t.Fatalf("Expected migrations to run after force, got %v", err)
+	}
+	latest, _ := migrate.LatestVersion()
+	if status, err := runner.Status(); err != nil || status.Version != latest || status.Dirty {
+		t.Errorf("Expected clean version %d, got %+v, %v", latest, status, err)
+	}
+}
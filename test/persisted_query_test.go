@@ -0,0 +1,130 @@
+package test
+
+import (
+	"encoding/json"
+	"go-graphql/internal/config"
+	"go-graphql/internal/graph/generated"
+	"go-graphql/internal/graph/persisted"
+	"go-graphql/internal/graph/resolvers"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+const typenameQuery = `{ __typename }`
+
+func newPersistedServer(extensions ...graphql.HandlerExtension) *handler.Server {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &resolvers.Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	for _, ext := range extensions {
+		srv.Use(ext)
+	}
+	return srv
+}
+
+// postPersisted sends query and hash, either may be empty, and returns the
+// error code of the response or its data
+func postPersisted(t *testing.T, srv http.Handler, query, hash string) (code string, data string) {
+	t.Helper()
+	payload := map[string]any{"query": query}
+	if hash != "" {
+		payload["extensions"] = map[string]any{
+			"persistedQuery": map[string]any{"version": 1, "sha256Hash": hash},
+		}
+	}
+	body, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Extensions map[string]any `json:"extensions"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		code, _ = resp.Errors[0].Extensions["code"].(string)
+	}
+	return code, string(resp.Data)
+}
+
+func TestAutomaticPersistedQueries(t *testing.T) {
+	mr := miniredis.RunT(t)
+	cfg := &config.Config{GraphQL: config.GraphQLCfg{APQTTL: 60}}
+	newInstance := func() *handler.Server {
+		store := newTestCacheStore(t, mr, config.RedisCfg{Codec: "json"})
+		return newPersistedServer(extension.AutomaticPersistedQuery{
+			Cache: persisted.NewQueryCache(store, cfg, zap.NewNop()),
+		})
+	}
+	first, second := newInstance(), newInstance()
+	hash := persisted.Hash(typenameQuery)
+
+	if code, _ := postPersisted(t, first, "", hash); code != persisted.CodeNotFound {
+		t.Fatalf("Expected %s for an unknown hash, got %q", persisted.CodeNotFound, code)
+	}
+	if code, data := postPersisted(t, first, typenameQuery, hash); code != "" || !strings.Contains(data, "Query") {
+		t.Fatalf("Expected the query to run and register, got %q %s", code, data)
+	}
+	// the hash is shared through Redis
+	if code, data := postPersisted(t, second, "", hash); code != "" || !strings.Contains(data, "Query") {
+		t.Errorf("Expected another instance to know the hash, got %q %s", code, data)
+	}
+	if ttl := mr.TTL("go-graphql-test:apq:" + hash); ttl.Minutes() != 60 {
+		t.Errorf("Expected the persisted query to expire in 60 minutes, got %v", ttl)
+	}
+}
+
+func TestTrustedDocuments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "trusted.json")
+	hash := persisted.Hash(typenameQuery)
+	manifest, _ := json.Marshal(map[string]string{hash: typenameQuery})
+	if err := os.WriteFile(path, manifest, 0o600); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	m, err := persisted.LoadManifest(path)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	srv := newPersistedServer(persisted.NewTrusted(m, true))
+
+	if code, data := postPersisted(t, srv, "", hash); code != "" || !strings.Contains(data, "Query") {
+		t.Errorf("Expected the trusted hash to run, got %q %s", code, data)
+	}
+	if code, _ := postPersisted(t, srv, typenameQuery, ""); code != "" {
+		t.Errorf("Expected the full trusted document to run, got %q", code)
+	}
+	if code, _ := postPersisted(t, srv, `{ a: __typename }`, ""); code != persisted.CodeNotAllowed {
+		t.Errorf("Expected %s for an arbitrary query, got %q", persisted.CodeNotAllowed, code)
+	}
+	if code, _ := postPersisted(t, srv, "", persisted.Hash(`{ a: __typename }`)); code != persisted.CodeNotFound {
+		t.Errorf("Expected %s for an unknown hash, got %q", persisted.CodeNotFound, code)
+	}
+
+	t.Run("Mismatched hash", func(t *testing.T) {
+		bad := filepath.Join(dir, "bad.json")
+		data, _ := json.Marshal(map[string]string{hash: `{ a: __typename }`})
+		if err := os.WriteFile(bad, data, 0o600); err != nil {
+			t.Fatalf("Failed to write manifest: %v", err)
+		}
+		if _, err := persisted.LoadManifest(bad); err == nil {
+			t.Error("Expected a manifest with a mismatched hash to be rejected")
+		}
+	})
+}
@@ -91,16 +91,16 @@ func adminListProduct(t *testing.T, product dto.ProductResponse, addr string, to
 			return
 		}
 
-		var products dto.ClientListProductsResponse
-		if err := json.NewDecoder(resp.Body).Decode(&products); err != nil {
+		var page dto.ProductPage
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 			t.Fatalf(FailedToDecodeMessage, err)
 		}
 
-		if len(products) == 0 {
+		if len(page.Items) == 0 || page.Total == 0 {
 			t.Errorf("Expected at least one product, got 0")
 		}
 		findCreatedProduct := false
-		for index, p := range products {
+		for index, p := range page.Items {
 			if p.ID == product.ID {
 				findCreatedProduct = true
 				t.Logf("Found created product at index %d: %+v", index, p)
@@ -44,16 +44,19 @@ func clientListProducts(t *testing.T, product dto.ProductResponse, addr string)
 			return
 		}
 
-		var products dto.ClientListProductsResponse
-		if err := json.NewDecoder(resp.Body).Decode(&products); err != nil {
+		var page dto.ProductPage
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 			t.Fatalf(FailedToDecodeMessage, err)
 		}
 
 		found := false
-		for _, p := range products {
+		for _, p := range page.Items {
 			if p.ID == product.ID {
 				found = true
 				t.Logf("Found product in client list: %+v", p)
+				if !p.IsActive {
+					t.Errorf("Expected the active product to be listed with isActive true")
+				}
 				break
 			}
 		}
@@ -89,5 +92,8 @@ func clientGetProductByID(t *testing.T, product dto.ProductResponse, addr string
 		if fetchedProduct.Name != product.Name {
 			t.Errorf("Expected product name %q, got %q", product.Name, fetchedProduct.Name)
 		}
+		if !fetchedProduct.IsActive {
+			t.Errorf("Expected the active product to have isActive true")
+		}
 	})
 }
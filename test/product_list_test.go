@@ -0,0 +1,191 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-graphql/internal/config"
+	"go-graphql/internal/graph/generated"
+	"go-graphql/internal/graph/resolvers"
+	"go-graphql/internal/product/controller"
+	"go-graphql/internal/product/dto"
+	product "go-graphql/internal/product/service"
+	"go-graphql/internal/storage/sql/sqlc"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+func TestListProductsValidation(t *testing.T) {
+	cfg := &config.Config{GraphQL: config.GraphQLCfg{MaxLimit: 100}}
+	svc := product.New(nil, nil, zap.NewNop(), nil, cfg)
+
+	limit, offset := 10, 10
+	tests := []struct {
+		name  string
+		query dto.ProductListQuery
+		err   error
+	}{
+		{name: "Unknown sort", query: dto.ProductListQuery{Sort: "-stock"}, err: product.ErrInvalidSort},
+		{name: "Malformed cursor", query: dto.ProductListQuery{Cursor: "not a cursor"}, err: product.ErrInvalidCursor},
+		{name: "Cursor of another sort", query: dto.ProductListQuery{Sort: "name", Cursor: "eyJzIjoiLWNyZWF0ZWRBdCIsImkiOjF9"}, err: product.ErrInvalidCursor},
+		{name: "Offset with a cursor", query: dto.ProductListQuery{Limit: &limit, Offset: &offset, Cursor: "eyJzIjoiaWQiLCJpIjoxfQ"}, err: product.ErrInvalidPagination},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.List(context.Background(), tt.query)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestListProductsPageLinks(t *testing.T) {
+	mr := miniredis.RunT(t)
+	store := newTestCacheStore(t, mr, config.RedisCfg{Codec: "json"})
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var all []dto.ProductResponse
+	for id := int32(5); id >= 1; id-- {
+		all = append(all, dto.ProductResponse{ID: id, Name: "Product", Version: 1, CreatedAt: created.Add(time.Duration(id) * time.Hour)})
+	}
+	if err := store.Set(context.Background(), store.KeyAllProducts(), all, 1); err != nil {
+		t.Fatalf("Failed to seed the product list: %v", err)
+	}
+
+	// the unfiltered newest first list is paged from the cache, no
+	// database is needed
+	svc := product.New(nil, nil, zap.NewNop(), store, &config.Config{GraphQL: config.GraphQLCfg{MaxLimit: 100}})
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	controller.NewClient(svc).RegisterRoutes(r.Group("/api/v1/products"))
+
+	t.Run("Offset page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/?limit=2&offset=2", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 OK, got %d: %s", w.Code, w.Body.String())
+		}
+		var page dto.ProductPage
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf(FailedToDecodeMessage, err)
+		}
+		if page.Total != 5 || page.Limit != 2 || page.Offset != 2 || len(page.Items) != 2 || page.Items[0].ID != 3 {
+			t.Errorf("Unexpected page %+v", page)
+		}
+		if page.NextCursor == "" {
+			t.Errorf("Expected a next cursor")
+		}
+		want := `</api/v1/products/?limit=2>; rel="first", </api/v1/products/?limit=2>; rel="prev", ` +
+			`</api/v1/products/?limit=2&offset=4>; rel="next", </api/v1/products/?limit=2&offset=4>; rel="last"`
+		if got := w.Header().Get("Link"); got != want {
+			t.Errorf("Expected Link %s, got %s", want, got)
+		}
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/?limit=2&offset=4", nil))
+		var page dto.ProductPage
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf(FailedToDecodeMessage, err)
+		}
+		if len(page.Items) != 1 || page.NextCursor != "" {
+			t.Errorf("Unexpected last page %+v", page)
+		}
+		want := `</api/v1/products/?limit=2>; rel="first", </api/v1/products/?limit=2&offset=2>; rel="prev", ` +
+			`</api/v1/products/?limit=2&offset=4>; rel="last"`
+		if got := w.Header().Get("Link"); got != want {
+			t.Errorf("Expected Link %s, got %s", want, got)
+		}
+	})
+
+	t.Run("Invalid sort", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/?sort=stock", nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+// newCatalogPostgres serves an active and an inactive product to the list
+// queries, and the inactive one to GetProduct
+func newCatalogPostgres(t *testing.T) *fakePostgres {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	active := []any{int32(7), "Active product", "Listed", int64(1000), true, created, int32(1)}
+	inactive := []any{int32(8), "Inactive product", "Unlisted", int64(2000), false, created, int32(1)}
+	columns := []uint32{pgtype.Int4OID, pgtype.TextOID, pgtype.TextOID, pgtype.Int8OID, pgtype.BoolOID, pgtype.TimestampOID, pgtype.Int4OID}
+	filters := []uint32{pgtype.Int4OID, pgtype.TextOID, pgtype.Int8OID, pgtype.Int8OID, pgtype.BoolOID, pgtype.TextOID}
+	return newFakePostgres(t, "products", func(query string) fakeReply {
+		switch {
+		case strings.Contains(query, "CountProductsWithFilters"):
+			return fakeReply{Columns: []uint32{pgtype.Int8OID}, Rows: [][]any{{int64(2)}}, Params: filters}
+		case strings.Contains(query, "ListProductsWithFilters"):
+			params := append(append([]uint32{}, filters...),
+				pgtype.Int4OID, pgtype.TextOID, pgtype.TextOID, pgtype.Int8OID, pgtype.TimestampOID, pgtype.Int8OID, pgtype.Int8OID)
+			return fakeReply{Columns: columns, Rows: [][]any{active, inactive}, Params: params}
+		case strings.Contains(query, "GetProduct"):
+			return fakeReply{Columns: columns, Rows: [][]any{inactive}}
+		case strings.Contains(query, "FROM products"):
+			return fakeReply{Columns: columns, Rows: [][]any{active, inactive}}
+		}
+		return fakeReply{}
+	})
+}
+
+func TestProductsReportIsActive(t *testing.T) {
+	pool, err := pgxpool.New(context.Background(), newCatalogPostgres(t).DSN())
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	store := newTestCacheStore(t, miniredis.RunT(t), config.RedisCfg{Codec: "json"})
+	svc := product.New(sqlc.New(pool), nil, zap.NewNop(), store, &config.Config{GraphQL: config.GraphQLCfg{MaxLimit: 100}})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	controller.NewClient(svc).RegisterRoutes(r.Group("/api/v1/products"))
+
+	t.Run("REST list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/", nil))
+		// decoded as maps, a missing isActive must not pass for false
+		var page struct {
+			Items []map[string]any `json:"items"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatalf(FailedToDecodeMessage, err)
+		}
+		if len(page.Items) != 2 || page.Items[0]["isActive"] != true || page.Items[1]["isActive"] != false {
+			t.Errorf("Expected isActive true then false, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("REST get", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/8", nil))
+		if !strings.Contains(w.Body.String(), `"isActive":false`) {
+			t.Errorf("Expected isActive false, got %d %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("GraphQL", func(t *testing.T) {
+		srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &resolvers.Resolver{ProductService: svc}}))
+		srv.AddTransport(transport.POST{})
+		_, data := postPersisted(t, srv, `{ products { products { id isActive } total } }`, "")
+		if want := `{"products":{"products":[{"id":7,"isActive":true},{"id":8,"isActive":false}],"total":2}}`; data != want {
+			t.Errorf("Expected %s, got %s", want, data)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"encoding/json"
+	"go-graphql/internal/config"
+	"go-graphql/internal/graph/generated"
+	"go-graphql/internal/graph/resolvers"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestLimiter(t *testing.T, mr *miniredis.Miniredis, rl config.RateLimitCfg) *ratelimit.Limiter {
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	rl.Enabled = true
+	cfg := &config.Config{Redis: config.RedisCfg{Prefix: "go-graphql-test"}, RateLimit: rl}
+	return ratelimit.New(cfg, client, zap.NewNop())
+}
+
+func TestRateLimitREST(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	limiter := newTestLimiter(t, mr, config.RateLimitCfg{ClientRate: 60, ClientBurst: 2})
+
+	r := gin.New()
+	r.GET("/products", middleware.RateLimit(limiter, ratelimit.GroupClient), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := get("10.0.0.1:1234")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200, got %d", i, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("Request %d: expected RateLimit-Remaining %s, got %q", i, wantRemaining, got)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("Request %d: expected RateLimit-Limit 2, got %q", i, got)
+		}
+	}
+
+	rec := get("10.0.0.1:1234")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 once the bucket is empty, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", got)
+	}
+
+	// another client has its own bucket
+	if rec := get("10.0.0.2:1234"); rec.Code != http.StatusOK {
+		t.Errorf("Expected another IP to pass, got %d", rec.Code)
+	}
+}
+
+func TestRateLimitFailOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, tc := range []struct {
+		failOpen bool
+		want     int
+	}{
+		{failOpen: true, want: http.StatusOK},
+		{failOpen: false, want: http.StatusServiceUnavailable},
+	} {
+		mr := miniredis.RunT(t)
+		limiter := newTestLimiter(t, mr, config.RateLimitCfg{AdminRate: 60, FailOpen: tc.failOpen})
+		mr.Close()
+
+		r := gin.New()
+		r.GET("/admin", middleware.RateLimit(limiter, ratelimit.GroupAdmin), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+		if rec.Code != tc.want {
+			t.Errorf("FailOpen %t: expected status %d, got %d", tc.failOpen, tc.want, rec.Code)
+		}
+	}
+}
+
+func TestRateLimitGraphQLChargesCost(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	limiter := newTestLimiter(t, mr, config.RateLimitCfg{GraphQLRate: 60, GraphQLBurst: 4})
+
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &resolvers.Resolver{}}))
+	srv.AddTransport(transport.POST{})
+	srv.Use(limiter.GraphQL())
+	r := gin.New()
+	r.POST("/query", middleware.GraphQLRateLimit(limiter), gin.WrapH(srv))
+
+	post := func(query string) (*httptest.ResponseRecorder, string) {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		var resp struct {
+			Errors []struct {
+				Extensions map[string]any `json:"extensions"`
+			} `json:"errors"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		code := ""
+		if len(resp.Errors) > 0 {
+			code, _ = resp.Errors[0].Extensions["code"].(string)
+		}
+		return rec, code
+	}
+
+	// three fields cost three tokens
+	rec, code := post(`{ a: __typename b: __typename c: __typename }`)
+	if rec.Code != http.StatusOK || code != "" {
+		t.Fatalf("Expected the first query to pass, got %d %q", rec.Code, code)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "1" {
+		t.Errorf("Expected RateLimit-Remaining 1, got %q", got)
+	}
+
+	rec, code = post(`{ a: __typename b: __typename }`)
+	if rec.Code != http.StatusTooManyRequests || code != ratelimit.CodeRateLimited {
+		t.Errorf("Expected 429 %s, got %d %q", ratelimit.CodeRateLimited, rec.Code, code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header")
+	}
+
+	_, code = post(`{ a: __typename b: __typename c: __typename d: __typename e: __typename }`)
+	if code != ratelimit.CodeCostExceedsRate {
+		t.Errorf("Expected %s for a query costing more than the burst, got %q", ratelimit.CodeCostExceedsRate, code)
+	}
+}
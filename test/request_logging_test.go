@@ -0,0 +1,64 @@
+package test
+
+import (
+	"errors"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/http/response"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newLoggedEngine() (*gin.Engine, *observer.ObservedLogs) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core)
+	r := gin.New()
+	r.Use(middleware.RequestID(log), middleware.RequestLogger(log))
+	r.GET("/products/:id", func(c *gin.Context) {
+		response.JSONError(c, http.StatusNotFound, errors.New("product not found"))
+	})
+	return r, logs
+}
+
+func TestRequestIDPropagated(t *testing.T) {
+	r, logs := newLoggedEngine()
+	req := httptest.NewRequest(http.MethodGet, "/products/7", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(middleware.RequestIDHeader); got != "req-123" {
+		t.Fatalf("Expected request id req-123, got %q", got)
+	}
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.ContextMap()["request_id"] != "req-123" {
+			t.Errorf("Expected request_id in %q entry, got %v", entry.Message, entry.ContextMap())
+		}
+	}
+	served := entries[1].ContextMap()
+	if served["route"] != "/products/:id" || served["status"] != int64(http.StatusNotFound) {
+		t.Errorf("Unexpected request log fields: %v", served)
+	}
+}
+
+func TestRequestIDGeneratedWhenInvalid(t *testing.T) {
+	r, _ := newLoggedEngine()
+	req := httptest.NewRequest(http.MethodGet, "/products/7", nil)
+	req.Header.Set(middleware.RequestIDHeader, "bad id\n")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	got := rec.Header().Get(middleware.RequestIDHeader)
+	if got == "" || got == "bad id\n" {
+		t.Fatalf("Expected a generated request id, got %q", got)
+	}
+}
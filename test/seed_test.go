@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-graphql/internal/config"
+	"go-graphql/internal/storage"
+	"go-graphql/internal/storage/seed"
+	"go-graphql/internal/storage/sql/migrate"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+func TestSeedResetNotAllowedInProduction(t *testing.T) {
+	loader := seed.NewLoader(nil, &config.Config{ENV: "production"})
+
+	_, err := loader.LoadFiles(context.Background(), true, "../fixtures")
+	if !errors.Is(err, seed.ErrResetNotAllowed) {
+		t.Fatalf("Expected ErrResetNotAllowed, got %v", err)
+	}
+}
+
+func TestSeedRejectsUnknownFixtureKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.yaml")
+	if err := os.WriteFile(path, []byte("orders:\n  - id: 1\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	loader := seed.NewLoader(nil, &config.Config{ENV: "test"})
+
+	_, err := loader.LoadFiles(context.Background(), false, path)
+	if err == nil || !strings.Contains(err.Error(), `unknown fixture key "orders"`) {
+		t.Fatalf("Expected unknown fixture key error, got %v", err)
+	}
+}
+
+func writeProductFixture(t *testing.T, price int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "products.yaml")
+	fixture := fmt.Sprintf("products:\n  - id: 1\n    name: Lamp\n    description: Desk lamp\n    price: %d\n", price)
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	return path
+}
+
+func TestSeedCountsOnlyWrittenRows(t *testing.T) {
+	db := newFakePostgres(t, "seed", func(query string) fakeReply {
+		if strings.Contains(query, "INSERT INTO products") {
+			// the row already matches its fixture
+			return fakeReply{Tag: "INSERT 0 0", Params: []uint32{
+				pgtype.Int4OID, pgtype.TextOID, pgtype.TextOID, pgtype.Int8OID, pgtype.BoolOID,
+			}}
+		}
+		return fakeReply{}
+	})
+	loader := seed.NewLoader(newTestTxManager(t, db, 0), &config.Config{ENV: "test"})
+
+	result, err := loader.LoadFiles(context.Background(), false, writeProductFixture(t, 1000))
+	if err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+	if result["products"] != 0 {
+		t.Errorf("Expected no written product, got %d", result["products"])
+	}
+}
+
+func TestReseedKeepsProductVersions(t *testing.T) {
+	cfg, conn := requireMigrationDatabase(t)
+	ctx := context.Background()
+	if err := migrate.NewRunner(cfg).Up(ctx); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	pool, err := pgxpool.New(ctx, cfg.Database.DSN)
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	cfg.ENV = "test"
+	loader := seed.NewLoader(storage.NewTxManager(pool, cfg, zap.NewNop()), cfg)
+
+	version := func() int {
+		var v int
+		if err := conn.QueryRow(ctx, "SELECT version FROM products WHERE id = 1").Scan(&v); err != nil {
+			t.Fatalf("Failed to read version: %v", err)
+		}
+		return v
+	}
+	for _, step := range []struct {
+		price   int
+		written int
+		version int
+	}{
+		{price: 1000, written: 1, version: 1},
+		{price: 1000, written: 0, version: 1},
+		{price: 1200, written: 1, version: 2},
+	} {
+		result, err := loader.LoadFiles(ctx, false, writeProductFixture(t, step.price))
+		if err != nil {
+			t.Fatalf("Failed to seed: %v", err)
+		}
+		if result["products"] != step.written || version() != step.version {
+			t.Errorf("Seeding price %d: expected %d written and version %d, got %d written and version %d",
+				step.price, step.written, step.version, result["products"], version())
+		}
+	}
+}
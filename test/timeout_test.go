@@ -0,0 +1,41 @@
+package test
+
+import (
+	"go-graphql/internal/http/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeoutCancelsSlowHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	canceled := make(chan struct{})
+	r := gin.New()
+	r.GET("/slow", middleware.Timeout(50*time.Millisecond), func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		close(canceled)
+	})
+	r.GET("/fast", middleware.Timeout(time.Second), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Errorf("Expected the handler context to be canceled")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rec.Code)
+	}
+}
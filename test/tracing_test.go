@@ -0,0 +1,79 @@
+package test
+
+import (
+	"go-graphql/internal/graph/persisted"
+	"go-graphql/internal/http/middleware"
+	"go-graphql/internal/http/response"
+	"go-graphql/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTracingContinuesCallerTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(t.Context())
+
+	core, logs := observer.New(zap.InfoLevel)
+	log := zap.New(core)
+	r := gin.New()
+	r.Use(middleware.Tracing("go-graphql-test", tp), middleware.RequestID(log))
+	r.GET("/products/:id", func(c *gin.Context) {
+		response.JSONError(c, http.StatusNotFound, response.ErrNotFound)
+	})
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/products/7", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, /health is not traced, got %d", len(spans))
+	}
+	if got := spans[0].SpanContext.TraceID().String(); got != traceID {
+		t.Errorf("Expected trace id %s, got %s", traceID, got)
+	}
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].ContextMap()["trace_id"] != traceID {
+		t.Errorf("Expected the error log to carry trace_id %s, got %v", traceID, entries)
+	}
+}
+
+func TestGraphQLTracingExportsDocumentHash(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(t.Context())
+
+	const query = `query Secret { a: __typename }`
+	postPersisted(t, newPersistedServer(tracing.NewGraphQL(tp)), query, "")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "query Secret" {
+		t.Fatalf("Expected the operation span, got %v", spans)
+	}
+	attrs := map[attribute.Key]string{}
+	for _, attr := range spans[0].Attributes {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	if attrs["graphql.document.hash"] != persisted.Hash(query) {
+		t.Errorf("Expected the document hash, got %v", attrs)
+	}
+	if _, ok := attrs["graphql.document"]; ok {
+		t.Errorf("Expected the document itself not to be exported")
+	}
+}
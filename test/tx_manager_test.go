@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"go-graphql/internal/config"
+	"go-graphql/internal/storage"
+	"go-graphql/internal/storage/sql/sqlc"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// serializable is how pgx opens the TxManager transactions
+const serializable = "begin isolation level serializable"
+
+func newTestTxManager(t *testing.T, db *fakePostgres, maxRetries int) *storage.TxManager {
+	t.Helper()
+	pool, err := pgxpool.New(context.Background(), db.DSN())
+	if err != nil {
+		t.Fatalf("Failed to create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	cfg := &config.Config{Database: config.DatabaseCfg{TxMaxRetries: maxRetries}}
+	return storage.NewTxManager(pool, cfg, zap.NewNop())
+}
+
+func expectStatements(t *testing.T, db *fakePostgres, want ...string) {
+	t.Helper()
+	if got := db.Statements(); !slices.Equal(got, want) {
+		t.Errorf("Expected statements %q, got %q", want, got)
+	}
+}
+
+func TestTxManagerNestedRollbackKeepsOuterTx(t *testing.T) {
+	db := newFakePostgres(t, "tx", nil)
+	tx := newTestTxManager(t, db, 0)
+	errNested := errors.New("nested failed")
+	var hooks []string
+
+	err := tx.WithinTx(context.Background(), func(ctx context.Context, _ *sqlc.Queries) error {
+		storage.AfterCommit(ctx, func(context.Context) { hooks = append(hooks, "outer") })
+		err := tx.WithinTx(ctx, func(ctx context.Context, _ *sqlc.Queries) error {
+			storage.AfterCommit(ctx, func(context.Context) { hooks = append(hooks, "nested") })
+			return errNested
+		})
+		if !errors.Is(err, errNested) {
+			t.Errorf("Expected the nested error, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the outer transaction to commit, got %v", err)
+	}
+	expectStatements(t, db, serializable, "savepoint sp_1", "rollback to savepoint sp_1", "commit")
+	if !slices.Equal(hooks, []string{"outer"}) {
+		t.Errorf("Expected only the outer hook to run, got %v", hooks)
+	}
+}
+
+func TestTxManagerNestedCommitReleasesSavepoint(t *testing.T) {
+	db := newFakePostgres(t, "tx", nil)
+	tx := newTestTxManager(t, db, 0)
+	var ranAfter []string
+
+	err := tx.WithinTx(context.Background(), func(ctx context.Context, _ *sqlc.Queries) error {
+		return tx.WithinTx(ctx, func(ctx context.Context, _ *sqlc.Queries) error {
+			storage.AfterCommit(ctx, func(context.Context) {
+				// hooks of a savepoint wait for the outermost commit
+				statements := db.Statements()
+				ranAfter = append(ranAfter, statements[len(statements)-1])
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("Expected commit, got %v", err)
+	}
+	expectStatements(t, db, serializable, "savepoint sp_1", "release savepoint sp_1", "commit")
+	if !slices.Equal(ranAfter, []string{"commit"}) {
+		t.Errorf("Expected the hook to run once after commit, ran after %v", ranAfter)
+	}
+}
+
+func TestTxManagerRetriesConflicts(t *testing.T) {
+	for _, code := range []string{pgerrcode.SerializationFailure, pgerrcode.DeadlockDetected} {
+		t.Run(code, func(t *testing.T) {
+			db := newFakePostgres(t, "tx", nil)
+			tx := newTestTxManager(t, db, 2)
+			attempts := 0
+			hookRan := false
+
+			err := tx.WithinTx(context.Background(), func(ctx context.Context, _ *sqlc.Queries) error {
+				attempts++
+				storage.AfterCommit(ctx, func(context.Context) { hookRan = true })
+				return &pgconn.PgError{Code: code}
+			})
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) || pgErr.Code != code {
+				t.Fatalf("Expected the %s error once retries are exhausted, got %v", code, err)
+			}
+			if attempts != 3 {
+				t.Errorf("Expected 1 attempt and 2 retries, got %d attempts", attempts)
+			}
+			expectStatements(t, db, serializable, "rollback", serializable, "rollback", serializable, "rollback")
+			if hookRan {
+				t.Errorf("Expected hooks of rolled back attempts to be dropped")
+			}
+		})
+	}
+}
+
+func TestTxManagerRetriesFailedCommit(t *testing.T) {
+	commits := 0
+	db := newFakePostgres(t, "tx", func(query string) fakeReply {
+		if query == "commit" {
+			commits++
+			if commits == 1 {
+				return fakeReply{Code: pgerrcode.SerializationFailure}
+			}
+		}
+		return fakeReply{}
+	})
+	tx := newTestTxManager(t, db, 3)
+	attempts, hooks := 0, 0
+
+	err := tx.WithinTx(context.Background(), func(ctx context.Context, _ *sqlc.Queries) error {
+		attempts++
+		storage.AfterCommit(ctx, func(context.Context) { hooks++ })
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected the retry to commit, got %v", err)
+	}
+	if attempts != 2 || hooks != 1 {
+		t.Errorf("Expected 2 attempts and 1 hook run, got %d attempts and %d hook runs", attempts, hooks)
+	}
+}
+
+func TestTxManagerDoesNotRetryOtherErrors(t *testing.T) {
+	db := newFakePostgres(t, "tx", nil)
+	tx := newTestTxManager(t, db, 3)
+	attempts := 0
+
+	err := tx.WithinTx(context.Background(), func(context.Context, *sqlc.Queries) error {
+		attempts++
+		return &pgconn.PgError{Code: pgerrcode.UniqueViolation}
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("Expected one failed attempt, got %d attempts and %v", attempts, err)
+	}
+	expectStatements(t, db, serializable, "rollback")
+}
+
+func TestAfterCommitOutsideTxRunsAtOnce(t *testing.T) {
+	ran := false
+	storage.AfterCommit(context.Background(), func(context.Context) { ran = true })
+	if !ran {
+		t.Errorf("Expected the hook to run immediately outside a transaction")
+	}
+}